@@ -1,12 +1,17 @@
 package jsonapi
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -24,6 +29,7 @@ import (
 	"github.com/neuronlabs/neuron/log"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 	"github.com/neuronlabs/neuron/server"
 )
 
@@ -42,18 +48,36 @@ type API struct {
 	// Endpoints are API endpoints slice created after initialization.
 	Endpoints []*server.Endpoint
 
-	handlers       map[*mapping.ModelStruct]interface{}
-	models         map[*mapping.ModelStruct]struct{}
-	defaultHandler *DefaultHandler
+	handlers          map[*mapping.ModelStruct]interface{}
+	models            map[*mapping.ModelStruct]struct{}
+	readOnlyRelations map[*mapping.StructField]struct{}
+	aliases           map[*mapping.ModelStruct][]string
+	defaultHandler    *DefaultHandler
+	flights           flightGroup
+	draining          int32
+	includesDisabled  int32
+	semaphores        map[*mapping.ModelStruct]chan struct{}
+	deprecated        map[deprecatedEndpoint]time.Time
+	pageSizes         map[*mapping.ModelStruct]int
+	idParams          map[*mapping.ModelStruct]string
+	nestedRoutes      map[*mapping.StructField]struct{}
+	listFields        map[*mapping.ModelStruct]mapping.FieldSet
+	getFields         map[*mapping.ModelStruct]mapping.FieldSet
 }
 
 // New creates new jsonapi API API for the Default Controller.
 func New(options ...Option) *API {
 	a := &API{
-		Options:        &Options{PayloadLinks: true},
-		handlers:       map[*mapping.ModelStruct]interface{}{},
-		models:         map[*mapping.ModelStruct]struct{}{},
-		defaultHandler: &DefaultHandler{},
+		Options:           &Options{PayloadLinks: true, MaxFilterDepth: -1, MaxSortDepth: -1, MaxResponseBytes: DefaultMaxResponseBytes},
+		handlers:          map[*mapping.ModelStruct]interface{}{},
+		models:            map[*mapping.ModelStruct]struct{}{},
+		readOnlyRelations: map[*mapping.StructField]struct{}{},
+		aliases:           map[*mapping.ModelStruct][]string{},
+		deprecated:        map[deprecatedEndpoint]time.Time{},
+		pageSizes:         map[*mapping.ModelStruct]int{},
+		idParams:          map[*mapping.ModelStruct]string{},
+		nestedRoutes:      map[*mapping.StructField]struct{}{},
+		defaultHandler:    &DefaultHandler{},
 	}
 	for _, option := range options {
 		option(a.Options)
@@ -74,9 +98,22 @@ func (a *API) InitializeAPI(options server.Options) error {
 	a.Authenticator = options.Authenticator
 
 	a.Options.Middlewares = append(server.MiddlewareChain{
+		MidRequestID,
+		a.midDraining,
+		midResponseHeader,
 		middleware.Controller(options.Controller),
 		middleware.WithCodec(jsonapi.GetCodec(options.Controller)),
 	}, a.Options.Middlewares...)
+	if len(a.Options.Profiles) > 0 {
+		a.Options.Middlewares = append(server.MiddlewareChain{a.midProfiles}, a.Options.Middlewares...)
+	}
+	if a.Options.AllowDebugPretty {
+		a.Options.Middlewares = append(server.MiddlewareChain{midDebugPretty}, a.Options.Middlewares...)
+	}
+
+	if a.Options.DisableIncludes {
+		a.SetIncludesDisabled(true)
+	}
 
 	// Check if there are any models registered for given API.
 	if len(a.Options.DefaultHandlerModels) == 0 && len(a.Options.ModelHandlers) == 0 {
@@ -88,11 +125,39 @@ func (a *API) InitializeAPI(options server.Options) error {
 		return errors.WrapDetf(server.ErrServerOptions, "provided default page size with negative value: %d", a.Options.DefaultPageSize)
 	}
 
-	// Check if the base path has absolute value - if not add the leading slash to the BasePath.
-	if !path.IsAbs(a.Options.PathPrefix) {
-		a.Options.PathPrefix = "/" + a.Options.PathPrefix
+	// Check the maximum number of relationship members.
+	if a.Options.MaxRelationshipMembers < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum relationship members with negative value: %d", a.Options.MaxRelationshipMembers)
+	}
+
+	// Check the maximum number of NDJSON results.
+	if a.Options.NDJSONMaxResults < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum NDJSON results with negative value: %d", a.Options.NDJSONMaxResults)
+	}
+
+	// Check the maximum number of sort fields.
+	if a.Options.MaxSortFields < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum sort fields with negative value: %d", a.Options.MaxSortFields)
 	}
 
+	// Check the maximum number of included relations.
+	if a.Options.MaxIncludeBreadth < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum include breadth with negative value: %d", a.Options.MaxIncludeBreadth)
+	}
+
+	// Check the maximum response size.
+	if a.Options.MaxResponseBytes < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum response bytes with negative value: %d", a.Options.MaxResponseBytes)
+	}
+
+	// Check the maximum number of concurrent requests per model.
+	if a.Options.MaxConcurrentPerModel < 0 {
+		return errors.WrapDetf(server.ErrServerOptions, "provided maximum concurrent requests per model with negative value: %d", a.Options.MaxConcurrentPerModel)
+	}
+
+	// Normalize the path prefix, so that it is absolute and has no trailing slash, i.e. '/', '/api'.
+	a.Options.PathPrefix = path.Clean("/" + a.Options.PathPrefix)
+
 	// Check the path prefix if it is valid url path.
 	if _, err := url.Parse(a.Options.PathPrefix); err != nil {
 		return errors.WrapDetf(server.ErrServerOptions, "provided invalid path prefix: %v - %v", a.Options.PathPrefix, err)
@@ -129,6 +194,127 @@ func (a *API) InitializeAPI(options server.Options) error {
 		a.models[mStruct] = struct{}{}
 	}
 
+	if a.Options.MaxConcurrentPerModel > 0 {
+		a.semaphores = a.modelSemaphores()
+	}
+
+	// Set read-only relationships.
+	for _, readOnly := range a.Options.ReadOnlyRelationships {
+		mStruct, err := a.Controller.ModelStruct(readOnly.Model)
+		if err != nil {
+			return err
+		}
+		if len(readOnly.Relations) == 0 {
+			for _, relation := range mStruct.RelationFields() {
+				a.readOnlyRelations[relation] = struct{}{}
+			}
+			continue
+		}
+		for _, relationName := range readOnly.Relations {
+			relation, ok := mStruct.RelationByName(relationName)
+			if !ok {
+				return errors.WrapDetf(server.ErrServerOptions, "read-only relation: '%s' not found for the model: '%s'", relationName, mStruct)
+			}
+			a.readOnlyRelations[relation] = struct{}{}
+		}
+	}
+
+	// Set nested collection routes.
+	for _, nested := range a.Options.NestedRoutes {
+		mStruct, err := a.Controller.ModelStruct(nested.Model)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.models[mStruct]; !ok {
+			return errors.WrapDetf(server.ErrServerOptions, "nested routes set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		for _, relationName := range nested.Relations {
+			relation, ok := mStruct.RelationByName(relationName)
+			if !ok {
+				return errors.WrapDetf(server.ErrServerOptions, "nested route relation: '%s' not found for the model: '%s'", relationName, mStruct)
+			}
+			if relation.Relationship().Kind() != mapping.RelHasMany {
+				return errors.WrapDetf(server.ErrServerOptions, "nested route relation: '%s' of the model: '%s' is not a has many relationship", relationName, mStruct)
+			}
+			if _, ok = a.models[relation.Relationship().RelatedModelStruct()]; !ok {
+				return errors.WrapDetf(server.ErrServerOptions, "nested route relation: '%s' related model: '%s' not registered in the json:api", relationName, relation.Relationship().RelatedModelStruct())
+			}
+			a.nestedRoutes[relation] = struct{}{}
+		}
+	}
+
+	// Set model id url parameter names.
+	for _, modelIDParam := range a.Options.IDParams {
+		mStruct, err := a.Controller.ModelStruct(modelIDParam.Model)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.models[mStruct]; !ok {
+			return errors.WrapDetf(server.ErrServerOptions, "id url parameter set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		if modelIDParam.Param == "" || strings.ContainsAny(modelIDParam.Param, "/:*") {
+			return errors.WrapDetf(server.ErrServerOptions, "invalid id url parameter name: '%s' for the model: '%s'", modelIDParam.Param, mStruct)
+		}
+		a.idParams[mStruct] = modelIDParam.Param
+	}
+
+	// Set model default fieldsets.
+	var err error
+	if a.listFields, err = a.defaultFieldSets(a.Options.ListDefaultFields); err != nil {
+		return err
+	}
+	if a.getFields, err = a.defaultFieldSets(a.Options.GetDefaultFields); err != nil {
+		return err
+	}
+
+	// Set model default page sizes.
+	for _, modelPageSize := range a.Options.ModelPageSizes {
+		mStruct, err := a.Controller.ModelStruct(modelPageSize.Model)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.models[mStruct]; !ok {
+			return errors.WrapDetf(server.ErrServerOptions, "default page size set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		if modelPageSize.PageSize < 0 {
+			return errors.WrapDetf(server.ErrServerOptions, "provided default page size for the model: '%s' with negative value: %d", mStruct, modelPageSize.PageSize)
+		}
+		a.pageSizes[mStruct] = modelPageSize.PageSize
+	}
+
+	// Set deprecated endpoints.
+	for _, deprecated := range a.Options.DeprecatedEndpoints {
+		mStruct, err := a.Controller.ModelStruct(deprecated.Model)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.models[mStruct]; !ok {
+			return errors.WrapDetf(server.ErrServerOptions, "deprecated endpoint set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		a.deprecated[deprecatedEndpoint{model: mStruct, method: deprecated.QueryMethod}] = deprecated.Sunset
+	}
+
+	// Set collection aliases.
+	collections := map[string]*mapping.ModelStruct{}
+	for mStruct := range a.models {
+		collections[mStruct.Collection()] = mStruct
+	}
+	for _, modelAliases := range a.Options.CollectionAliases {
+		mStruct, err := a.Controller.ModelStruct(modelAliases.Model)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.models[mStruct]; !ok {
+			return errors.WrapDetf(server.ErrServerOptions, "collection aliases set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		for _, alias := range modelAliases.Aliases {
+			if other, ok := collections[alias]; ok {
+				return errors.WrapDetf(server.ErrServerOptions, "collection alias: '%s' for the model: '%s' is already used by the model: '%s'", alias, mStruct, other)
+			}
+			collections[alias] = mStruct
+			a.aliases[mStruct] = append(a.aliases[mStruct], alias)
+		}
+	}
 	return nil
 }
 
@@ -137,42 +323,104 @@ func (a *API) SetRoutes(router *httprouter.Router) error {
 	for model := range a.models {
 		// Set routes for the model
 		modelHandler, _ := a.handlers[model]
-		// Insert
-		a.setInsertRoute(router, modelHandler, model)
-		// Insert Relations
-		for _, relation := range model.RelationFields() {
-			a.setInsertRelationRoute(router, modelHandler, model, relation)
-		}
+		// The routes are set for the model collection and all its aliases.
+		for _, collection := range a.collections(model) {
+			// Insert
+			a.setInsertRoute(router, modelHandler, model, collection)
+			// Insert Relations
+			for _, relation := range a.mutableRelations(model) {
+				a.setInsertRelationRoute(router, modelHandler, model, collection, relation)
+			}
+
+			// deleteQuery
+			a.setDeleteRoute(router, modelHandler, model, collection)
+			// deleteQuery Relations
+			for _, relation := range a.mutableRelations(model) {
+				a.setDeleteRelationRoute(router, modelHandler, model, collection, relation)
+			}
+
+			// Get
+			a.setGetRoute(router, modelHandler, model, collection)
+			// Get related and get relationship routes.
+			for _, relation := range model.RelationFields() {
+				if _, ok := a.nestedRoutes[relation]; ok {
+					a.setNestedListRoute(router, model, collection, relation)
+				} else {
+					a.setGetRelationRoute(router, modelHandler, model, collection, relation)
+				}
+				a.setGetRelationshipRoute(router, modelHandler, model, collection, relation)
+			}
+			// List
+			a.setListRoute(router, modelHandler, model, collection)
 
-		// deleteQuery
-		a.setDeleteRoute(router, modelHandler, model)
-		// deleteQuery Relations
-		for _, relation := range model.RelationFields() {
-			a.setDeleteRelationRoute(router, modelHandler, model, relation)
+			// Patch
+			a.setUpdateRoute(router, modelHandler, model, collection)
+			// Patch relations
+			for _, relation := range a.mutableRelations(model) {
+				a.setUpdateRelationRoute(router, modelHandler, model, collection, relation)
+			}
 		}
+	}
+	if a.Options.AllowMethodOverride {
+		// The overridden POST requests on the paths without the POST routes are not allowed by the router.
+		router.HandleMethodNotAllowed = true
+		router.MethodNotAllowed = a.methodOverrideFallback(router, router.MethodNotAllowed)
+	}
+	if a.Options.IndexRoute {
+		a.setIndexRoute(router)
+	}
+	return nil
+}
 
-		// Get
-		a.setGetRoute(router, modelHandler, model)
-		// Get related and get relationship routes.
-		for _, relation := range model.RelationFields() {
-			a.setGetRelationRoute(router, modelHandler, model, relation)
-			a.setGetRelationshipRoute(router, modelHandler, model, relation)
+// collections gets the model collection name followed by its aliases.
+func (a *API) collections(model *mapping.ModelStruct) []string {
+	return append([]string{model.Collection()}, a.aliases[model]...)
+}
+
+// middlewares gets a copy of the API global middlewares, so that the endpoint chains doesn't share the same array.
+func (a *API) middlewares() server.MiddlewareChain {
+	return append(server.MiddlewareChain{}, a.Options.Middlewares...)
+}
+
+// defaultFieldSets resolves the model default fieldsets from the 'modelFields' options.
+func (a *API) defaultFieldSets(modelFields []ModelFields) (map[*mapping.ModelStruct]mapping.FieldSet, error) {
+	fieldSets := map[*mapping.ModelStruct]mapping.FieldSet{}
+	for _, mf := range modelFields {
+		mStruct, err := a.Controller.ModelStruct(mf.Model)
+		if err != nil {
+			return nil, err
 		}
-		// List
-		a.setListRoute(router, modelHandler, model)
+		if _, ok := a.models[mStruct]; !ok {
+			return nil, errors.WrapDetf(server.ErrServerOptions, "default fields set for the model: '%s' not registered in the json:api", mStruct)
+		}
+		fieldSet := mapping.FieldSet{}
+		for _, name := range mf.Fields {
+			field, ok := mStruct.Attribute(name)
+			if !ok {
+				field, ok = mStruct.RelationByName(name)
+			}
+			if !ok {
+				return nil, errors.WrapDetf(server.ErrServerOptions, "default field: '%s' not found for the model: '%s'", name, mStruct)
+			}
+			fieldSet = append(fieldSet, field)
+		}
+		fieldSets[mStruct] = fieldSet
+	}
+	return fieldSets, nil
+}
 
-		// Patch
-		a.setUpdateRoute(router, modelHandler, model)
-		// Patch relations
-		for _, relation := range model.RelationFields() {
-			a.setUpdateRelationRoute(router, modelHandler, model, relation)
+// mutableRelations gets the model relations that are not read-only.
+func (a *API) mutableRelations(model *mapping.ModelStruct) (relations []*mapping.StructField) {
+	for _, relation := range model.RelationFields() {
+		if _, readOnly := a.readOnlyRelations[relation]; !readOnly {
+			relations = append(relations, relation)
 		}
 	}
-	return nil
+	return relations
 }
 
-func (a *API) setInsertRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct) {
-	endpointPath := fmt.Sprintf("/%s", model.Collection())
+func (a *API) setInsertRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string) {
+	endpointPath := fmt.Sprintf("/%s", collection)
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -183,16 +431,16 @@ func (a *API) setInsertRoute(router *httprouter.Router, modelHandler interface{}
 		ModelStruct: model,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	insertChain := append(a.Options.Middlewares, MidContentType, httputil.MidStoreEndpoint(endpoint))
+	insertChain := append(a.middlewares(), MidContentType, httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if insertMiddlewarer, ok := modelHandler.(server.InsertMiddlewarer); ok {
 		insertChain = append(insertChain, insertMiddlewarer.InsertMiddlewares()...)
 	}
 	log.Debugf("POST %s", endpointPath)
-	router.POST(endpointPath, httputil.Wrap(insertChain.Handle(a.handleInsert(model))))
+	router.POST(endpointPath, a.overridable(router, httputil.Wrap(insertChain.Handle(a.handleInsert(model)))))
 }
 
-func (a *API) setInsertRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, relation *mapping.StructField) {
-	endpointPath := fmt.Sprintf("/%s/:id/relationships/%s", model.Collection(), relation.NeuronName())
+func (a *API) setInsertRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/relationships/%s", collection, a.idParam(model), relation.NeuronName())
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -204,16 +452,16 @@ func (a *API) setInsertRelationRoute(router *httprouter.Router, modelHandler int
 		Relation:    relation,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidContentType, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidContentType, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if insertMiddlewarer, ok := modelHandler.(server.InsertRelationsMiddlewarer); ok {
 		chain = append(chain, insertMiddlewarer.InsertRelationsMiddlewares()...)
 	}
 	log.Debugf("POST %s ", endpointPath)
-	router.POST(endpointPath, httputil.Wrap(chain.Handle(a.handleInsertRelationship(model, relation))))
+	router.POST(endpointPath, a.overridable(router, httputil.Wrap(chain.Handle(a.handleInsertRelationship(model, relation)))))
 }
 
-func (a *API) setDeleteRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct) {
-	endpointPath := fmt.Sprintf("/%s/:id", model.Collection())
+func (a *API) setDeleteRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string) {
+	endpointPath := fmt.Sprintf("/%s/:%s", collection, a.idParam(model))
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -224,7 +472,7 @@ func (a *API) setDeleteRoute(router *httprouter.Router, modelHandler interface{}
 		ModelStruct: model,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.DeleteMiddlewarer); ok {
 		chain = append(chain, middlewarer.DeleteMiddlewares()...)
 	}
@@ -232,8 +480,8 @@ func (a *API) setDeleteRoute(router *httprouter.Router, modelHandler interface{}
 	router.DELETE(endpointPath, httputil.Wrap(chain.Handle(a.handleDelete(model))))
 }
 
-func (a *API) setDeleteRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, relation *mapping.StructField) {
-	endpointPath := fmt.Sprintf("/%s/:id/relationships/%s", model.Collection(), relation.NeuronName())
+func (a *API) setDeleteRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/relationships/%s", collection, a.idParam(model), relation.NeuronName())
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -245,7 +493,7 @@ func (a *API) setDeleteRelationRoute(router *httprouter.Router, modelHandler int
 		Relation:    relation,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidContentType, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidContentType, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.DeleteRelationsMiddlewarer); ok {
 		chain = append(chain, middlewarer.DeleteRelationsMiddlewares()...)
 	}
@@ -253,8 +501,8 @@ func (a *API) setDeleteRelationRoute(router *httprouter.Router, modelHandler int
 	router.DELETE(endpointPath, httputil.Wrap(chain.Handle(a.handleDeleteRelationship(model, relation))))
 }
 
-func (a *API) setGetRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct) {
-	endpointPath := fmt.Sprintf("/%s/:id", model.Collection())
+func (a *API) setGetRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string) {
+	endpointPath := fmt.Sprintf("/%s/:%s", collection, a.idParam(model))
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -265,16 +513,19 @@ func (a *API) setGetRoute(router *httprouter.Router, modelHandler interface{}, m
 		ModelStruct: model,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidAccept, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidAccept, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.GetMiddlewarer); ok {
 		chain = append(chain, middlewarer.GetMiddlewares()...)
 	}
+	if a.Options.SingleFlightReads {
+		chain = append(chain, a.midSingleFlight)
+	}
 	log.Debugf("GET %s", endpointPath)
 	router.GET(endpointPath, httputil.Wrap(chain.Handle(a.handleGet(model))))
 }
 
-func (a *API) setGetRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, relation *mapping.StructField) {
-	endpointPath := fmt.Sprintf("/%s/:id/%s", model.Collection(), relation.NeuronName())
+func (a *API) setGetRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/%s", collection, a.idParam(model), relation.NeuronName())
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -286,7 +537,7 @@ func (a *API) setGetRelationRoute(router *httprouter.Router, modelHandler interf
 		Relation:    relation,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidAccept, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidAccept, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.GetRelationMiddlewarer); ok {
 		chain = append(chain, middlewarer.GetRelatedMiddlewares()...)
 	}
@@ -294,8 +545,8 @@ func (a *API) setGetRelationRoute(router *httprouter.Router, modelHandler interf
 	router.GET(endpointPath, httputil.Wrap(chain.Handle(a.handleGetRelated(model, relation))))
 }
 
-func (a *API) setGetRelationshipRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, relation *mapping.StructField) {
-	endpointPath := fmt.Sprintf("/%s/:id/relationships/%s", model.Collection(), relation.NeuronName())
+func (a *API) setGetRelationshipRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/relationships/%s", collection, a.idParam(model), relation.NeuronName())
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -307,7 +558,7 @@ func (a *API) setGetRelationshipRoute(router *httprouter.Router, modelHandler in
 		Relation:    relation,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chainRelated := append(a.Options.Middlewares, MidAccept, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chainRelated := append(a.middlewares(), MidAccept, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.GetRelationMiddlewarer); ok {
 		chainRelated = append(chainRelated, middlewarer.GetRelatedMiddlewares()...)
 	}
@@ -315,8 +566,8 @@ func (a *API) setGetRelationshipRoute(router *httprouter.Router, modelHandler in
 	router.GET(endpointPath, httputil.Wrap(chainRelated.Handle(a.handleGetRelationship(model, relation))))
 }
 
-func (a *API) setListRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct) {
-	endpointPath := fmt.Sprintf("/%s", model.Collection())
+func (a *API) setListRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string) {
+	endpointPath := fmt.Sprintf("/%s", collection)
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -327,16 +578,19 @@ func (a *API) setListRoute(router *httprouter.Router, modelHandler interface{},
 		ModelStruct: model,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidAccept, httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), a.midListAccept, httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.ListMiddlewarer); ok {
 		chain = append(chain, middlewarer.ListMiddlewares()...)
 	}
+	if a.Options.SingleFlightReads {
+		chain = append(chain, a.midSingleFlight)
+	}
 	log.Debugf("GET %s", endpointPath)
 	router.GET(endpointPath, httputil.Wrap(chain.Handle(a.handleList(model))))
 }
 
-func (a *API) setUpdateRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct) {
-	endpointPath := fmt.Sprintf("/%s/:id", model.Collection())
+func (a *API) setUpdateRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string) {
+	endpointPath := fmt.Sprintf("/%s/:%s", collection, a.idParam(model))
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -347,7 +601,7 @@ func (a *API) setUpdateRoute(router *httprouter.Router, modelHandler interface{}
 		ModelStruct: model,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidContentType, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidContentType, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.UpdateMiddlewarer); ok {
 		chain = append(chain, middlewarer.UpdateMiddlewares()...)
 	}
@@ -355,8 +609,8 @@ func (a *API) setUpdateRoute(router *httprouter.Router, modelHandler interface{}
 	router.PATCH(endpointPath, httputil.Wrap(chain.Handle(a.handleUpdate(model))))
 }
 
-func (a *API) setUpdateRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, relation *mapping.StructField) {
-	endpointPath := fmt.Sprintf("/%s/:id/relationships/%s", model.Collection(), relation.NeuronName())
+func (a *API) setUpdateRelationRoute(router *httprouter.Router, modelHandler interface{}, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/relationships/%s", collection, a.idParam(model), relation.NeuronName())
 	if a.Options.PathPrefix != "/" {
 		endpointPath = a.Options.PathPrefix + endpointPath
 	}
@@ -368,7 +622,7 @@ func (a *API) setUpdateRelationRoute(router *httprouter.Router, modelHandler int
 		Relation:    relation,
 	}
 	a.Endpoints = append(a.Endpoints, endpoint)
-	chain := append(a.Options.Middlewares, MidContentType, middleware.StoreIDFromParams("id"), httputil.MidStoreEndpoint(endpoint))
+	chain := append(a.middlewares(), MidContentType, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint), a.midTracing, a.midModelConcurrency, a.midDeprecation)
 	if middlewarer, ok := modelHandler.(server.UpdateRelationsMiddlewarer); ok {
 		chain = append(chain, middlewarer.UpdateRelationsMiddlewares()...)
 	}
@@ -376,6 +630,23 @@ func (a *API) setUpdateRelationRoute(router *httprouter.Router, modelHandler int
 	router.PATCH(endpointPath, httputil.Wrap(chain.Handle(a.handleUpdateRelationship(model, relation))))
 }
 
+// idParam gets the name of the model id url parameter used in the route patterns, 'id' by default.
+func (a *API) idParam(model *mapping.ModelStruct) string {
+	if param, ok := a.idParams[model]; ok {
+		return param
+	}
+	return "id"
+}
+
+// readDB gets the database used by the read endpoints - the ReadDB option if set, otherwise the API database.
+// The writes and the reads done within the write transactions always use the API database.
+func (a *API) readDB() database.DB {
+	if a.Options.ReadDB != nil {
+		return a.Options.ReadDB
+	}
+	return a.DB
+}
+
 func (a *API) basePath() string {
 	if a.Options.PathPrefix == "" {
 		return "/"
@@ -383,20 +654,56 @@ func (a *API) basePath() string {
 	return a.Options.PathPrefix
 }
 
+// baseModelPath gets the path of the model collection, i.e. '/prefix/collection'. It never contains
+// the double slashes, regardless if the PathPrefix is empty, root or ends with a slash.
 func (a *API) baseModelPath(mStruct *mapping.ModelStruct) string {
 	return path.Join("/", a.Options.PathPrefix, mStruct.Collection())
 }
 
-func (a *API) writeContentType(rw http.ResponseWriter) {
-	rw.Header().Add("Content-Type", jsonapi.MimeType)
+// linksBaseURL gets the base url of the links marshaled by the codec. The root path prefix has the empty base url,
+// so that the links don't start with the double slash.
+func (a *API) linksBaseURL() string {
+	if a.Options.PathPrefix == "/" {
+		return ""
+	}
+	return a.Options.PathPrefix
+}
+
+func (a *API) writeContentType(rw http.ResponseWriter, profiles ...string) {
+	if len(profiles) == 0 {
+		rw.Header().Set("Content-Type", jsonapi.MimeType)
+		return
+	}
+	// Echo applied profiles in the 'profile' media type parameter.
+	rw.Header().Set("Content-Type", mime.FormatMediaType(jsonapi.MimeType, map[string]string{"profile": strings.Join(profiles, " ")}))
 }
 
 func (a *API) jsonapiUnmarshalOptions() *codec.UnmarshalOptions {
 	return &codec.UnmarshalOptions{StrictUnmarshal: a.Options.StrictUnmarshal}
 }
 
-func (a *API) marshalErrors(rw http.ResponseWriter, status int, err error) {
-	errs := httputil.MapError(err)
+func (a *API) marshalErrors(rw http.ResponseWriter, req *http.Request, status int, err error) {
+	if a.Options.ErrorStatusMapper != nil {
+		if mapped, ok := a.Options.ErrorStatusMapper(err); ok {
+			status = mapped
+		}
+	}
+	if span, ok := ctxSpan(req.Context()); ok {
+		span.RecordError(err)
+	}
+	errs, isViolation := violationErrors(err)
+	if !isViolation {
+		errs = httputil.MapError(err)
+	}
+	// Set the request id so that the error could be matched with the server logs.
+	if id, ok := CtxRequestID(req.Context()); ok && id != "" {
+		for _, e := range errs {
+			if e.Meta == nil {
+				e.Meta = make(map[string]interface{})
+			}
+			e.Meta["requestId"] = id
+		}
+	}
 	a.writeContentType(rw)
 	// If no status is defined - set default from the errors.
 	if status == 0 {
@@ -411,11 +718,39 @@ func (a *API) marshalErrors(rw http.ResponseWriter, status int, err error) {
 	}
 }
 
-func (a *API) marshalPayload(rw http.ResponseWriter, payload *codec.Payload, status int) {
-	a.writeContentType(rw)
+func (a *API) marshalPayload(rw http.ResponseWriter, req *http.Request, payload *codec.Payload, status int, rewriters ...documentRewriter) {
 	buf := &bytes.Buffer{}
-	payloadMarshaler := jsonapi.GetCodec(a.Controller).(codec.PayloadMarshaler)
-	if err := payloadMarshaler.MarshalPayload(buf, payload); err != nil {
+	var err error
+	if a.Options.ResponsePostProcessor != nil {
+		err = a.Options.ResponsePostProcessor(payload, req)
+	}
+	if err == nil {
+		payloadMarshaler := jsonapi.GetCodec(a.Controller).(codec.PayloadMarshaler)
+		err = payloadMarshaler.MarshalPayload(buf, payload)
+	}
+	if a.Options.OmitEmptyRelationships {
+		rewriters = append(rewriters, omitEmptyRelationships)
+	}
+	if a.Options.StableIncludedOrder {
+		rewriters = append(rewriters, sortIncluded)
+	}
+	if a.Options.DescribedBy != nil && payload.ModelStruct != nil {
+		if describedBy := a.Options.DescribedBy(payload.ModelStruct); describedBy != "" {
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("links", map[string]interface{}{"describedby": describedBy})
+			})
+		}
+	}
+	if err == nil && len(rewriters) > 0 {
+		err = rewriteDocument(buf, rewriters)
+	}
+	if err == nil {
+		err = a.encodeResponse(req, buf)
+	}
+	if err != nil {
+		log.Errorf("Marshaling payload failed: %v", err)
+		discardPayloadHeaders(rw, req)
+		a.writeContentType(rw)
 		rw.WriteHeader(500)
 		err := jsonapi.GetCodec(a.Controller).MarshalErrors(rw, httputil.ErrInternalError())
 		if err != nil {
@@ -428,12 +763,35 @@ func (a *API) marshalPayload(rw http.ResponseWriter, payload *codec.Payload, sta
 		}
 		return
 	}
+	if a.Options.MaxResponseBytes > 0 && buf.Len() > a.Options.MaxResponseBytes {
+		log.Debugf("[%s] %s response size: %d exceeds the limit: %d", req.Method, req.URL.Path, buf.Len(), a.Options.MaxResponseBytes)
+		discardPayloadHeaders(rw, req)
+		a.marshalErrors(rw, req, http.StatusRequestEntityTooLarge, newError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Response size exceeds the limit of %d bytes. Narrow the response with the 'fields', 'include' or 'page' parameters.", a.Options.MaxResponseBytes)))
+		return
+	}
+	a.writeContentType(rw, CtxProfiles(req.Context())...)
 	rw.WriteHeader(status)
 	if _, err := rw.Write(buf.Bytes()); err != nil {
 		log.Errorf("Writing to response writer failed: %v", err)
 	}
 }
 
+// payloadHeaders are the response headers set by the handlers that describe only the successful response payload.
+var payloadHeaders = []string{"ETag", "Location", "Content-Range", "Content-Language"}
+
+// discardPayloadHeaders removes the payloadHeaders already set for the response, so that they are not written
+// along with the error replacing the payload.
+func discardPayloadHeaders(rw http.ResponseWriter, req *http.Request) {
+	collected, hasCollector := CtxResponseHeader(req.Context())
+	for _, name := range payloadHeaders {
+		rw.Header().Del(name)
+		if hasCollector {
+			collected.Del(name)
+		}
+	}
+}
+
 func (a *API) createListScope(model *mapping.ModelStruct, req *http.Request) (*query.Scope, error) {
 	// Create a query scope and parse url parameters.
 	s := query.NewScope(model)
@@ -444,13 +802,307 @@ func (a *API) createListScope(model *mapping.ModelStruct, req *http.Request) (*q
 		return nil, errors.WrapDet(errors.ErrInternal, "jsonapi codec doesn't implement ParameterParser")
 	}
 
-	parameters := query.MakeParameters(req.URL.Query())
+	if err := a.checkQueryParameters(req); err != nil {
+		return nil, err
+	}
+	if err := a.checkIncludes(model, req); err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	// The search filter is not a model field filter - it is handled by the model's Searcher.
+	searchTerms, isSearch := values[ParamFilterSearch]
+	if isSearch {
+		delete(values, ParamFilterSearch)
+	}
+	// The literal 'null' filter values are the 'is null' and 'not null' filters, not the string comparisons.
+	nullFilters, err := takeNullFilters(model, values)
+	if err != nil {
+		return nil, err
+	}
+	values, err = a.includeFieldSets(model, a.resolveFieldsAliases(values))
+	if err != nil {
+		return nil, err
+	}
+	parameters := query.MakeParameters(values)
 	if err := parser.ParseParameters(a.Controller, s, parameters); err != nil {
 		return nil, err
 	}
+	for _, nullFilter := range nullFilters {
+		s.Filter(nullFilter)
+	}
+	if err := a.checkFilterableFields(model, s.Filters, ""); err != nil {
+		return nil, err
+	}
+	if a.Options.HeaderScopeEnricher != nil {
+		if err := a.Options.HeaderScopeEnricher(req, s); err != nil {
+			return nil, err
+		}
+	}
+	if nested, ok := ctxNestedList(req.Context()); ok {
+		s.Filter(nested.filter)
+	}
+	if isSearch {
+		searcher, ok := a.handlers[model].(Searcher)
+		if !ok {
+			return nil, errInvalidParameter(ParamFilterSearch, fmt.Sprintf("Searching is not supported for the collection: '%s'.", model.Collection()))
+		}
+		if err := searcher.Search(req.Context(), s, strings.Join(searchTerms, " ")); err != nil {
+			return nil, err
+		}
+	}
 	return s, nil
 }
 
+// checkQueryParameters checks the request query parameters against the API options.
+// If the RejectUnknownParams option is set, all the parameters needs to be known json:api parameters.
+// If the MaxFilterDepth is not negative, the filter field paths could not exceed given number of relations.
+// If the MaxSortDepth is not negative, the sort field paths could not exceed given number of relations.
+// If the MaxSortFields is set, the number of sort fields could not exceed it.
+// The json:api parameters other than filters could not be provided more than once (i.e. duplicated 'fields[type]'),
+// as the precedence of their values would be undefined.
+func (a *API) checkQueryParameters(req *http.Request) error {
+	for key, values := range req.URL.Query() {
+		if len(values) > 1 && isKnownParameter(key) && !strings.HasPrefix(key, "filter[") {
+			log.Debug2f("Duplicated query parameter: '%s'", key)
+			return errInvalidParameter(key, fmt.Sprintf("Query parameter: '%s' is provided more than once.", key))
+		}
+		if a.Options.RejectUnknownParams && !isKnownParameter(key) {
+			log.Debug2f("Unknown query parameter: '%s'", key)
+			return errInvalidParameter(key, fmt.Sprintf("Unknown query parameter: '%s'.", key))
+		}
+		if a.Options.MaxFilterDepth >= 0 && strings.HasPrefix(key, "filter[") {
+			if depth := strings.Count(key, "."); depth > a.Options.MaxFilterDepth {
+				log.Debug2f("Filter: '%s' depth: %d exceeds the limit: %d", key, depth, a.Options.MaxFilterDepth)
+				return errInvalidParameter(key, fmt.Sprintf("Filter: '%s' is nested too deep. The maximum depth is: %d.", key, a.Options.MaxFilterDepth))
+			}
+		}
+		if a.Options.MaxSortFields > 0 && key == "sort" {
+			for _, value := range values {
+				if count := len(strings.Split(value, ",")); count > a.Options.MaxSortFields {
+					log.Debug2f("Sort fields: %d exceeds the limit: %d", count, a.Options.MaxSortFields)
+					return errInvalidParameter(key, fmt.Sprintf("Too many sort fields. The maximum number is: %d.", a.Options.MaxSortFields))
+				}
+			}
+		}
+		if a.Options.MaxSortDepth >= 0 && key == "sort" {
+			for _, value := range values {
+				for _, field := range strings.Split(value, ",") {
+					if depth := strings.Count(field, "."); depth > a.Options.MaxSortDepth {
+						log.Debug2f("Sort field: '%s' depth: %d exceeds the limit: %d", field, depth, a.Options.MaxSortDepth)
+						return errInvalidParameter(key, fmt.Sprintf("Sort field: '%s' is nested too deep. The maximum depth is: %d.", strings.TrimPrefix(field, "-"), a.Options.MaxSortDepth))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkIncludes checks if all the relationship paths in the request 'include' parameter exists for the model.
+// If the model handler is an IncludeRestricter, the paths needs to be allowed by it. The number of distinct
+// top-level included relationships could not exceed the MaxIncludeBreadth. While the includes are disabled,
+// the 'include' parameter is rejected or dropped from the request, depending on the DropDisabledIncludes option.
+// The explicitly empty 'include' parameter means no included resources.
+func (a *API) checkIncludes(mStruct *mapping.ModelStruct, req *http.Request) error {
+	// An explicitly empty 'include=' parameter requests no included resources - it is dropped,
+	// so that it is not parsed as an include of the empty relationship path.
+	if values, ok := req.URL.Query()["include"]; ok && len(values) == 1 && values[0] == "" {
+		dropIncludes(req)
+		return nil
+	}
+	if a.IncludesDisabled() {
+		if _, ok := req.URL.Query()["include"]; ok {
+			if a.Options.DropDisabledIncludes {
+				log.Debug2f("Includes are disabled - dropping the include parameter for the model: '%s'", mStruct)
+				dropIncludes(req)
+				return nil
+			}
+			log.Debug2f("Includes are disabled - rejecting the include parameter for the model: '%s'", mStruct)
+			return errInvalidParameter("include", "Including related resources is temporarily disabled.")
+		}
+	}
+	restricter, isRestricted := a.handlers[mStruct].(IncludeRestricter)
+	topLevel := map[string]struct{}{}
+	for _, value := range req.URL.Query()["include"] {
+		for _, includePath := range strings.Split(value, ",") {
+			model := mStruct
+			var names []string
+			for _, segment := range strings.Split(includePath, ".") {
+				relation, ok := model.RelationByName(segment)
+				if !ok {
+					log.Debug2f("Included relation: '%s' not found in the model: '%s'", segment, model)
+					return errInvalidParameter("include", fmt.Sprintf("Relationship: '%s' of the include path: '%s' not found for the collection: '%s'.", segment, includePath, model.Collection()))
+				}
+				names = append(names, relation.NeuronName())
+				model = relation.Relationship().RelatedModelStruct()
+			}
+			if len(names) > 0 {
+				topLevel[names[0]] = struct{}{}
+			}
+			if isRestricted && !isIncludeAllowed(names, restricter.AllowedIncludes()) {
+				log.Debug2f("Include path: '%s' is not allowed for the model: '%s'", includePath, mStruct)
+				return errInvalidParameter("include", fmt.Sprintf("Including: '%s' is not allowed for the collection: '%s'.", includePath, mStruct.Collection()))
+			}
+		}
+	}
+	if a.Options.MaxIncludeBreadth > 0 && len(topLevel) > a.Options.MaxIncludeBreadth {
+		log.Debug2f("Included relationships: %d exceeds the limit: %d", len(topLevel), a.Options.MaxIncludeBreadth)
+		return errInvalidParameter("include", fmt.Sprintf("Too many relationships included. The maximum number is: %d.", a.Options.MaxIncludeBreadth))
+	}
+	return nil
+}
+
+// isIncludeAllowed checks if the include path relation 'names' is equal to or is a prefix of any of the 'allowed' paths.
+func isIncludeAllowed(names []string, allowed []string) bool {
+	for _, allowedPath := range allowed {
+		segments := strings.Split(allowedPath, ".")
+		if len(segments) < len(names) {
+			continue
+		}
+		matches := true
+		for i, name := range names {
+			if segments[i] != name {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveFieldsAliases replaces the collection aliases in the sparse fieldset parameters 'fields[alias]'
+// with the aliased model collection, so that the codec could resolve the model fields.
+// If the fields are provided for both the alias and the collection, the collection parameter takes precedence.
+func (a *API) resolveFieldsAliases(values url.Values) url.Values {
+	resolved := url.Values{}
+	for key, value := range values {
+		if strings.HasPrefix(key, "fields[") && strings.HasSuffix(key, "]") {
+			if mStruct, ok := a.aliasedModel(key[len("fields[") : len(key)-1]); ok {
+				collectionKey := "fields[" + mStruct.Collection() + "]"
+				if _, ok := values[collectionKey]; !ok {
+					resolved[collectionKey] = value
+				}
+				continue
+			}
+		}
+		resolved[key] = value
+	}
+	return resolved
+}
+
+// aliasedModel gets the model for which given 'alias' was set.
+func (a *API) aliasedModel(alias string) (*mapping.ModelStruct, bool) {
+	for mStruct, aliases := range a.aliases {
+		for _, modelAlias := range aliases {
+			if modelAlias == alias {
+				return mStruct, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// isKnownParameter checks if given query parameter 'key' is a json:api parameter.
+func isKnownParameter(key string) bool {
+	switch key {
+	case "include", "sort", "links":
+		return true
+	}
+	if bracket := strings.IndexRune(key, '['); bracket > 0 && strings.HasSuffix(key, "]") {
+		switch key[:bracket] {
+		case "filter", "fields", "page":
+			return true
+		}
+	}
+	return false
+}
+
+// prepareScope prepares the read query scope 's' using the DefaultScoper and ScopePreparer model handlers, if implemented.
+func (a *API) prepareScope(ctx context.Context, s *query.Scope, method query.Method) error {
+	modelHandler := a.handlers[s.ModelStruct]
+	if scoper, ok := modelHandler.(DefaultScoper); ok {
+		if err := applyDefaultFilters(ctx, scoper, s); err != nil {
+			return err
+		}
+	}
+	preparer, ok := modelHandler.(ScopePreparer)
+	if !ok {
+		return nil
+	}
+	return preparer.PrepareScope(ctx, s, method)
+}
+
+// applyDefaultFilters adds the default filters to the scope 's' for the fields not filtered by the client.
+func applyDefaultFilters(ctx context.Context, scoper DefaultScoper, s *query.Scope) error {
+	defaultFilters, err := scoper.DefaultFilters(ctx, s)
+	if err != nil {
+		return err
+	}
+	filtered := map[*mapping.StructField]struct{}{}
+	for _, f := range s.Filters {
+		if field := filterField(f); field != nil {
+			filtered[field] = struct{}{}
+		}
+	}
+	for _, f := range defaultFilters {
+		if field := filterField(f); field != nil {
+			if _, ok := filtered[field]; ok {
+				continue
+			}
+		}
+		s.Filter(f)
+	}
+	return nil
+}
+
+// filterField gets the field of the simple or relation filter.
+func filterField(f filter.Filter) *mapping.StructField {
+	switch ft := f.(type) {
+	case filter.Simple:
+		return ft.StructField
+	case filter.Relation:
+		return ft.StructField
+	}
+	return nil
+}
+
+// checkRelationshipBody checks if the relationship mutation request has a non-empty body, if the
+// RejectEmptyRelationshipBody option is set. The body that has an unknown length is peeked and replaced in the request.
+func (a *API) checkRelationshipBody(req *http.Request) error {
+	if !a.Options.RejectEmptyRelationshipBody {
+		return nil
+	}
+	empty := req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0
+	if !empty && req.ContentLength < 0 {
+		reader := bufio.NewReader(req.Body)
+		_, err := reader.Peek(1)
+		empty = err == io.EOF
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{Reader: reader, Closer: req.Body}
+	}
+	if empty {
+		err := httputil.ErrInvalidInput()
+		err.Detail = "relationship operations require a data member"
+		return err
+	}
+	return nil
+}
+
+// checkRelationshipMembers checks if the number of relationship members in the payload doesn't exceed the limit.
+func (a *API) checkRelationshipMembers(payload *codec.Payload) error {
+	if a.Options.MaxRelationshipMembers > 0 && len(payload.Data) > a.Options.MaxRelationshipMembers {
+		err := httputil.ErrInvalidInput()
+		err.Detail = fmt.Sprintf("Too many relationship members provided. The maximum number is: %d.", a.Options.MaxRelationshipMembers)
+		return err
+	}
+	return nil
+}
+
 func (a *API) params(req *http.Request) *server.Params {
 	params := &server.Params{
 		Ctx:           req.Context(),
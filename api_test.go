@@ -0,0 +1,184 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/server"
+)
+
+// testAPI is the json:api server for the Author and Blog models stored in the in-memory repository.
+type testAPI struct {
+	api     *API
+	repo    *testRepository
+	router  *httprouter.Router
+	blogs   *mapping.ModelStruct
+	authors *mapping.ModelStruct
+}
+
+// newTestAPI creates the test API with given 'options'. If 'wrapDB' is set, the API uses the database it returns.
+func newTestAPI(t *testing.T, wrapDB func(db database.DB) database.DB, options ...Option) *testAPI {
+	t.Helper()
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	repo := newTestRepository()
+	if err := c.MapRepositoryModels(repo, Neuron_Models...); err != nil {
+		t.Fatalf("mapping repository models failed: %v", err)
+	}
+	var db database.DB = database.New(c)
+	if wrapDB != nil {
+		db = wrapDB(db)
+	}
+	a := New(append([]Option{WithDefaultHandlerModels(Neuron_Models...)}, options...)...)
+	if err := a.InitializeAPI(server.Options{Controller: c, DB: db}); err != nil {
+		t.Fatalf("initializing API failed: %v", err)
+	}
+	router := httprouter.New()
+	if err := a.SetRoutes(router); err != nil {
+		t.Fatalf("setting routes failed: %v", err)
+	}
+	return &testAPI{api: a, repo: repo, router: router, blogs: c.MustModelStruct(&Blog{}), authors: c.MustModelStruct(&Author{})}
+}
+
+// storeBlogs stores the blogs and their authors in the test repository.
+func (ta *testAPI) storeBlogs(blogs ...*Blog) {
+	for _, blog := range blogs {
+		ta.repo.store(ta.blogs, blog)
+		if blog.AuthorID != 0 {
+			if _, ok := ta.repo.stored(ta.authors, blog.AuthorID); !ok {
+				ta.repo.store(ta.authors, &Author{ID: blog.AuthorID})
+			}
+		}
+	}
+}
+
+// storedBlog gets the blog with the 'id' stored in the test repository.
+func (ta *testAPI) storedBlog(t *testing.T, id int) *Blog {
+	t.Helper()
+	model, ok := ta.repo.stored(ta.blogs, id)
+	if !ok {
+		t.Fatalf("blog: %d not stored", id)
+	}
+	return model.(*Blog)
+}
+
+// serve sends the request with the json:api 'body' and optional 'headers' pairs to the test API.
+func (ta *testAPI) serve(method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", jsonapi.MimeType)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	ta.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// expectStatus fails the test if the response 'rec' doesn't have the 'status'.
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("expected status: %d, got: %d, body: %s", status, rec.Code, rec.Body.String())
+	}
+}
+
+// decodeDocument decodes the json:api document from the response 'rec'.
+func decodeDocument(t *testing.T, rec *httptest.ResponseRecorder) document {
+	t.Helper()
+	doc := document{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding response document failed: %v", err)
+	}
+	return doc
+}
+
+// primaryResource decodes the single primary data resource of the 'doc'.
+func primaryResource(t *testing.T, doc document) object {
+	t.Helper()
+	resource := object{}
+	if err := json.Unmarshal(doc["data"], &resource); err != nil {
+		t.Fatalf("decoding primary data failed: %v", err)
+	}
+	return resource
+}
+
+func TestPathPrefix(t *testing.T) {
+	for name, prefix := range map[string]string{"Unset": "", "Root": "/", "Relative": "api", "TrailingSlash": "/api/"} {
+		prefix := prefix
+		t.Run("Normalize"+name, func(t *testing.T) {
+			c := controller.NewDefault()
+			if err := c.RegisterModels(Neuron_Models...); err != nil {
+				t.Fatalf("registering models failed: %v", err)
+			}
+			a := New(WithDefaultHandlerModels(Neuron_Models...), WithPathPrefix(prefix))
+			if err := a.InitializeAPI(server.Options{Controller: c, DB: database.New(c)}); err != nil {
+				t.Fatalf("initializing API failed: %v", err)
+			}
+			expected, expectedBase := "/api", "/api"
+			if strings.Trim(prefix, "/") == "" {
+				expected, expectedBase = "/", ""
+			}
+			if a.Options.PathPrefix != expected {
+				t.Errorf("expected path prefix: '%s', got: '%s'", expected, a.Options.PathPrefix)
+			}
+			if base := a.linksBaseURL(); base != expectedBase {
+				t.Errorf("expected links base url: '%s', got: '%s'", expectedBase, base)
+			}
+			if modelPath := a.baseModelPath(c.MustModelStruct(&Blog{})); modelPath != expectedBase+"/blogs" {
+				t.Errorf("expected model path: '%s/blogs', got: '%s'", expectedBase, modelPath)
+			}
+		})
+	}
+
+	for name, prefix := range map[string]string{"Unset": "", "Root": "/"} {
+		prefix := prefix
+		t.Run("Links"+name, func(t *testing.T) {
+			options := []Option{WithPayloadLinks(true)}
+			if prefix != "" {
+				options = append(options, WithPathPrefix(prefix))
+			}
+			ta := newTestAPI(t, nil, options...)
+			ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2})
+
+			rec := ta.serve(http.MethodGet, "/blogs/1", "", "Accept", jsonapi.MimeType)
+			expectStatus(t, rec, http.StatusOK)
+			if strings.Contains(rec.Body.String(), "//") {
+				t.Errorf("get document contains double slash links: %s", rec.Body.String())
+			}
+			resource := primaryResource(t, decodeDocument(t, rec))
+			links := map[string]string{}
+			if err := json.Unmarshal(resource["links"], &links); err != nil {
+				t.Fatalf("decoding resource links failed: %v", err)
+			}
+			if links["self"] != "/blogs/1" {
+				t.Errorf("expected self link: '/blogs/1', got: '%s'", links["self"])
+			}
+
+			rec = ta.serve(http.MethodGet, "/blogs?page[limit]=1", "", "Accept", jsonapi.MimeType)
+			expectStatus(t, rec, http.StatusOK)
+			if strings.Contains(rec.Body.String(), "//") {
+				t.Errorf("list document contains double slash links: %s", rec.Body.String())
+			}
+
+			rec = ta.serve(http.MethodGet, "/blogs/1/relationships/author", "", "Accept", jsonapi.MimeType)
+			expectStatus(t, rec, http.StatusOK)
+			if strings.Contains(rec.Body.String(), "//") {
+				t.Errorf("relationship document contains double slash links: %s", rec.Body.String())
+			}
+		})
+	}
+}
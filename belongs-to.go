@@ -0,0 +1,47 @@
+package jsonapi
+
+import (
+	"context"
+
+	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// checkBelongsToExist checks if the resources referenced by the 'model' belongs to 'relations' exists in given 'db'.
+// The empty relations are not checked. The default filters of the related model handler are applied, so that
+// the resources out of its default scope are not referenced. If any of the referenced resources doesn't exist,
+// the related not found error is returned.
+func (a *API) checkBelongsToExist(ctx context.Context, db database.DB, model mapping.Model, relations []*mapping.StructField) error {
+	relationer, ok := model.(mapping.SingleRelationer)
+	if !ok {
+		return nil
+	}
+	for _, relation := range relations {
+		related, err := relationer.GetRelationModel(relation)
+		if err != nil {
+			return err
+		}
+		if related == nil || related.IsPrimaryKeyZero() {
+			continue
+		}
+		relatedStruct := relation.Relationship().RelatedModelStruct()
+		s := query.NewScope(relatedStruct)
+		s.Filter(filter.New(relatedStruct.Primary(), filter.OpEqual, related.GetPrimaryKeyValue()))
+		if scoper, ok := a.handlers[relatedStruct].(DefaultScoper); ok {
+			if err = applyDefaultFilters(ctx, scoper, s); err != nil {
+				return err
+			}
+		}
+		count, err := database.Count(ctx, db, s)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			id, _ := related.GetPrimaryKeyStringValue()
+			return errRelatedNotFound(relation, id)
+		}
+	}
+	return nil
+}
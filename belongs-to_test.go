@@ -0,0 +1,91 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// visibleAuthors is the author model handler with the default filter on the 'visible' name.
+type visibleAuthors struct{}
+
+// DefaultFilters implements DefaultScoper interface.
+func (visibleAuthors) DefaultFilters(_ context.Context, s *query.Scope) ([]filter.Filter, error) {
+	name, _ := s.ModelStruct.Attribute("name")
+	return []filter.Filter{filter.New(name, filter.OpEqual, "visible")}, nil
+}
+
+func TestValidateBelongsToExists(t *testing.T) {
+	// expectRelatedNotFound checks if the response is the '422 Unprocessable Entity' error pointing to the author relationship.
+	expectRelatedNotFound := func(t *testing.T, ta *testAPI, method, target, body string) {
+		t.Helper()
+		rec := ta.serve(method, target, body, "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusUnprocessableEntity)
+
+		var errs []struct {
+			Status string                 `json:"status"`
+			Meta   map[string]interface{} `json:"meta"`
+		}
+		if err := json.Unmarshal(decodeDocument(t, rec)["errors"], &errs); err != nil {
+			t.Fatalf("decoding errors failed: %v", err)
+		}
+		if len(errs) != 1 {
+			t.Fatalf("expected single error, got: %d", len(errs))
+		}
+		if errs[0].Status != "422" {
+			t.Errorf("expected error status: 422, got: %s", errs[0].Status)
+		}
+		if errs[0].Meta["pointer"] != "/data/relationships/author" {
+			t.Errorf("expected pointer: '/data/relationships/author', got: %v", errs[0].Meta["pointer"])
+		}
+	}
+
+	t.Run("InsertMissing", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithValidateBelongsToExists())
+
+		expectRelatedNotFound(t, ta, http.MethodPost, "/blogs", `{"data":{"type":"blogs","attributes":{"title":"x"},"relationships":{"author":{"data":{"type":"authors","id":"9"}}}}}`)
+		if models := ta.repo.models[ta.blogs]; len(models) != 0 {
+			t.Errorf("blog with missing author inserted: %v", models)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithValidateBelongsToExists())
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2})
+
+		expectRelatedNotFound(t, ta, http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","relationships":{"author":{"data":{"type":"authors","id":"9"}}}}}`)
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 2 {
+			t.Errorf("expected unchanged author id: 2, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("UpdateExisting", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithValidateBelongsToExists())
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2})
+		ta.repo.store(ta.authors, &Author{ID: 3})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","relationships":{"author":{"data":{"type":"authors","id":"3"}}}}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 3 {
+			t.Errorf("expected author id: 3, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("OutOfDefaultScope", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithValidateBelongsToExists(), WithModelHandler(&Author{}, visibleAuthors{}))
+		ta.repo.store(ta.authors, &Author{ID: 2, Name: "visible"}, &Author{ID: 3, Name: "hidden"})
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2})
+
+		// The author out of the default scope is not visible to the client, thus it cannot be referenced.
+		expectRelatedNotFound(t, ta, http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","relationships":{"author":{"data":{"type":"authors","id":"3"}}}}}`)
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 2 {
+			t.Errorf("expected unchanged author id: 2, got: %d", blog.AuthorID)
+		}
+	})
+}
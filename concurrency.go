@@ -0,0 +1,52 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// concurrencyRetryAfter is the number of seconds after which the client should retry the request refused
+// due to the model concurrency limit.
+const concurrencyRetryAfter = 1
+
+// modelSemaphores creates the semaphores that bound the number of concurrent requests for each API model.
+func (a *API) modelSemaphores() map[*mapping.ModelStruct]chan struct{} {
+	semaphores := map[*mapping.ModelStruct]chan struct{}{}
+	for mStruct := range a.models {
+		semaphores[mStruct] = make(chan struct{}, a.Options.MaxConcurrentPerModel)
+	}
+	return semaphores
+}
+
+// midModelConcurrency is the middleware that limits the number of concurrent requests handled for the model
+// of the stored endpoint. If the limit is reached, the request is refused with the '503 Service Unavailable' status.
+func (a *API) midModelConcurrency(next http.Handler) http.Handler {
+	if a.Options.MaxConcurrentPerModel == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		endpoint, ok := httputil.CtxGetEndpoint(req.Context())
+		if !ok {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		semaphore, ok := a.semaphores[endpoint.ModelStruct]
+		if !ok {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+			next.ServeHTTP(rw, req)
+		default:
+			log.Debugf("[%s] %s refused - concurrent requests limit reached for the model: '%s'", req.Method, req.URL.Path, endpoint.ModelStruct)
+			rw.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+			a.marshalErrors(rw, req, http.StatusServiceUnavailable, newError(http.StatusServiceUnavailable, "too many concurrent requests for the collection"))
+		}
+	})
+}
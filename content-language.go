@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// localeField gets the model attribute storing the resource locale, defined by the LocaleField option.
+func (a *API) localeField(mStruct *mapping.ModelStruct) (*mapping.StructField, bool) {
+	if a.Options.LocaleField == "" {
+		return nil, false
+	}
+	field, ok := mStruct.FieldByName(a.Options.LocaleField)
+	if !ok || field.Kind() != mapping.KindAttribute {
+		return nil, false
+	}
+	return field, true
+}
+
+// setContentLanguage sets the 'Content-Language' header to the locale stored in the 'field' of the 'models'.
+// If the models have different locales (i.e. list of mixed locales) the header is not set.
+func setContentLanguage(rw http.ResponseWriter, field *mapping.StructField, models []mapping.Model) error {
+	var language string
+	for _, model := range models {
+		fielder, ok := model.(mapping.Fielder)
+		if !ok {
+			return nil
+		}
+		value, err := fielder.GetFieldValue(field)
+		if err != nil {
+			return err
+		}
+		var locale string
+		switch v := value.(type) {
+		case string:
+			locale = v
+		case *string:
+			if v != nil {
+				locale = *v
+			}
+		default:
+			locale = fmt.Sprint(v)
+		}
+		if locale == "" || (language != "" && locale != language) {
+			return nil
+		}
+		language = locale
+	}
+	if language != "" {
+		rw.Header().Set("Content-Language", language)
+	}
+	return nil
+}
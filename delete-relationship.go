@@ -6,8 +6,10 @@ import (
 
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 	"github.com/neuronlabs/neuron/server"
 
 	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
@@ -36,15 +38,14 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			log.Debugf("[DELETE-RELATIONSHIP][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		model := mapping.NewModel(mStruct)
 		if err := model.SetPrimaryKeyStringValue(id); err != nil {
-			err := httputil.ErrInvalidQueryParameter()
-			err.Detail = "provided invalid 'id' value"
-			a.marshalErrors(rw, 0, err)
+			log.Debugf("[DELETE-RELATIONSHIP][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
@@ -52,7 +53,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value primary key"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -63,7 +64,13 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			StrictUnmarshal: a.Options.StrictUnmarshal,
 		})
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errUnmarshal(err))
+			return
+		}
+
+		if err := a.checkRelationshipMembers(payload); err != nil {
+			log.Debugf("[DELETE-RELATIONSHIP][%s][%s] %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
@@ -72,7 +79,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			if relation.IsPrimaryKeyZero() {
 				err := httputil.ErrInvalidJSONFieldValue()
 				err.Detail = "one of provided relationships doesn't have it's primary key value stored"
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 		}
@@ -83,12 +90,14 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			return
 		}
 
-		s := query.NewScope(mStruct, model)
+		// Get only the primary key with the current relation members.
+		s := query.NewScope(mStruct)
 		s.FieldSets = []mapping.FieldSet{{mStruct.Primary()}}
+		s.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
 
 		// Include relation values.
 		if err = s.Include(relation, relation.Relationship().RelatedModelStruct().Primary()); err != nil {
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
@@ -98,7 +107,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			if withCtx, ok := modelHandler.(server.WithContextDeleteRelationer); ok {
 				ctx, err = withCtx.DeleteRelationsWithContext(ctx)
 				if err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -107,7 +116,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		// Doing changes in the relationship requires to run it in a transaction.
 		tx, err := database.Begin(ctx, a.DB, nil)
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		defer func() {
@@ -118,57 +127,54 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 			}
 		}()
 
-		_, err = a.getHandleChain(ctx, tx, s)
+		var current *codec.Payload
+		current, err = a.getHandleChain(ctx, tx, s)
+		if err == nil && len(current.Data) == 0 {
+			err = errors.WrapDetf(query.ErrNoResult, "resource not found")
+		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if errors.Is(err, query.ErrNoResult) {
+				log.Debug2f("[DELETE-RELATIONSHIP][%s][%s] resource: '%s' not found", mStruct, relation, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
+		// The current relation members are taken from the stored model.
+		model = current.Data[0]
 
 		if hasModelHandler {
 			if beforeHandler, ok := modelHandler.(server.BeforeDeleteRelationsHandler); ok {
 				if err = beforeHandler.HandleBeforeDeleteRelations(ctx, tx, model, payload); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
 		}
 
 		var relationModels []mapping.Model
-		switch relation.Kind() {
-		case mapping.KindRelationshipMultiple:
-			mr, ok := model.(mapping.MultiRelationer)
-			if !ok {
-				a.marshalErrors(rw, 500, httputil.ErrInternalError())
-				return
-			}
-			models, err := mr.GetRelationModels(relation)
-			if err != nil {
-				a.marshalErrors(rw, 0, err)
-				return
-			}
+		if relationModels, err = currentRelationModels(model, relation); err != nil {
+			log.Errorf("[DELETE-RELATIONSHIP][%s][%s] getting current relations failed: %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+			return
+		}
 
-			for _, relationModel := range models {
-				relationModels = append(relationModels, relationModel)
-			}
-		case mapping.KindRelationshipSingle:
-			sr, ok := model.(mapping.SingleRelationer)
-			if !ok {
-				a.marshalErrors(rw, 500, httputil.ErrInternalError())
-				return
-			}
-			relationModel, err := sr.GetRelationModel(relation)
-			if err != nil {
-				a.marshalErrors(rw, 0, err)
-				return
-			}
-			relationModels = append(relationModels, relationModel)
+		if err = a.checkLinkageIfMatch(req, relationModels); err != nil {
+			log.Debugf("[DELETE-RELATIONSHIP][%s][%s] If-Match precondition failed: %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 0, err)
+			return
 		}
 
 		// Get the set of (current relations) - (to delete relations)  -> relations to set.
 		idMap := map[interface{}]int{}
 		var newRelations []mapping.Model
-		for i, current := range relationModels {
-			idMap[current.GetPrimaryKeyHashableValue()] = i
+		for i, member := range relationModels {
+			if member == nil {
+				// The empty to-one relationship has no members.
+				continue
+			}
+			idMap[member.GetPrimaryKeyHashableValue()] = i
 		}
 		nothingToDelete := true
 		for _, toDelete := range payload.Data {
@@ -187,7 +193,9 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		// If nothing is being deleted - json:api specify that this is successful request - and return no content status.
 		if nothingToDelete {
 			if err = tx.Commit(); err != nil {
-				log.Errorf("Committing transaction failed.")
+				log.Errorf("Committing transaction failed: %v", err)
+				a.marshalErrors(rw, req, 0, errCommit(err))
+				return
 			}
 			rw.WriteHeader(http.StatusNoContent)
 			return
@@ -202,7 +210,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		result, err = handler.HandleSetRelations(ctx, tx, model, newRelations, relation)
 		if err != nil {
 			log.Debug2f("[DELETE-RELATIONSHIP][%s][%s] HandleSetRelations failed %v", mStruct, relation, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -210,7 +218,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		if hasModelHandler {
 			if afterHandler, ok := modelHandler.(server.AfterDeleteRelationsHandler); ok {
 				if err = afterHandler.HandleAfterDeleteRelations(ctx, tx, model, newRelations, result); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -218,7 +226,7 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 
 		if err = tx.Commit(); err != nil {
 			log.Errorf("Committing transaction failed: %v", err)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 0, errCommit(err))
 			return
 		}
 		var hasJsonapiMimeType bool
@@ -242,12 +250,22 @@ func (a *API) handleDeleteRelationship(mStruct *mapping.ModelStruct, relation *m
 		result.FieldSets = []mapping.FieldSet{{relation.Relationship().RelatedModelStruct().Primary()}}
 		result.MarshalLinks = codec.LinkOptions{
 			Type:          link,
-			BaseURL:       a.Options.PathPrefix,
+			BaseURL:       a.linksBaseURL(),
 			RootID:        id,
 			Collection:    mStruct.Collection(),
 			RelationField: relation.NeuronName(),
 		}
 		result.MarshalSingularFormat = relation.Kind() == mapping.KindRelationshipSingle
-		a.marshalPayload(rw, result, http.StatusOK)
+		var rewriters []documentRewriter
+		if a.prefersPreviousLinkage(req) {
+			rewriter, err := previousLinkageRewriter(relation, relationModels)
+			if err != nil {
+				log.Errorf("[DELETE-RELATIONSHIP][%s][%s] getting previous relationship linkage failed: %v", mStruct, relation, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+			rewriters = append(rewriters, rewriter)
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
@@ -0,0 +1,35 @@
+package jsonapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+func TestHandleDeleteRelationshipIfMatch(t *testing.T) {
+	ta := newTestAPI(t, nil, WithETag())
+	ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 2})
+
+	rec := ta.serve(http.MethodGet, "/authors/2/relationships/blogs", "", "Accept", jsonapi.MimeType)
+	expectStatus(t, rec, http.StatusOK)
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag in the get relationship response")
+	}
+
+	rec = ta.serve(http.MethodDelete, "/authors/2/relationships/blogs", `{"data":[{"type":"blogs","id":"1"}]}`,
+		"Accept", jsonapi.MimeType, "If-Match", etag)
+	expectStatus(t, rec, http.StatusOK)
+
+	if blog := ta.storedBlog(t, 1); blog.AuthorID != 0 {
+		t.Errorf("expected blog: 1 without author, got author id: %d", blog.AuthorID)
+	}
+	if blog := ta.storedBlog(t, 2); blog.AuthorID != 2 {
+		t.Errorf("expected blog: 2 with author: 2, got author id: %d", blog.AuthorID)
+	}
+
+	rec = ta.serve(http.MethodDelete, "/authors/2/relationships/blogs", `{"data":[{"type":"blogs","id":"2"}]}`,
+		"Accept", jsonapi.MimeType, "If-Match", etag)
+	expectStatus(t, rec, http.StatusPreconditionFailed)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/server"
@@ -30,7 +31,7 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			log.Debugf("[DELETE] Empty id params: %v", id)
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "Provided empty id in the query URL"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -38,7 +39,7 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		err := model.SetPrimaryKeyStringValue(id)
 		if err != nil {
 			log.Debugf("[DELETE][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
@@ -46,7 +47,7 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value primary key for the model"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		// Create scope for the delete purpose.
@@ -58,7 +59,7 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if hasModelHandler {
 			if ctxSetter, ok := modelHandler.(server.WithContextDeleter); ok {
 				if ctx, err = ctxSetter.DeleteWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -82,7 +83,12 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			result, err = a.deleteHandlerChain(ctx, db, s)
 		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if a.Options.IdempotentDelete && errors.Is(err, query.ErrNoResult) {
+				log.Debug3f("[DELETE][%s] Resource: '%s' doesn't exist - returning HTTP Status: No Content - 204", mStruct.Collection(), id)
+				rw.WriteHeader(http.StatusNoContent)
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -91,7 +97,7 @@ func (a *API) handleDelete(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			rw.WriteHeader(http.StatusNoContent)
 			return
 		}
-		a.marshalPayload(rw, result, http.StatusOK)
+		a.marshalPayload(rw, req, result, http.StatusOK)
 	}
 }
 
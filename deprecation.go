@@ -0,0 +1,38 @@
+package jsonapi
+
+import (
+	"net/http"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// deprecatedEndpoint is the key of the deprecated model endpoint.
+type deprecatedEndpoint struct {
+	model  *mapping.ModelStruct
+	method query.Method
+}
+
+// midDeprecation is the middleware that sets the 'Deprecation' and 'Sunset' headers for the stored endpoint,
+// if it was marked as deprecated.
+func (a *API) midDeprecation(next http.Handler) http.Handler {
+	if len(a.deprecated) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		endpoint, ok := httputil.CtxGetEndpoint(req.Context())
+		if !ok {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		sunset, ok := a.deprecated[deprecatedEndpoint{model: endpoint.ModelStruct, method: endpoint.QueryMethod}]
+		if ok {
+			rw.Header().Set("Deprecation", "true")
+			if !sunset.IsZero() {
+				rw.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
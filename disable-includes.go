@@ -0,0 +1,29 @@
+package jsonapi
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// SetIncludesDisabled sets the API includes disabled state. While disabled, the 'include' parameter of the read
+// requests is rejected with the '400 Bad Request' status, or silently dropped if the DropDisabledIncludes option
+// is set. It allows to shed the load of the included relationships at runtime.
+func (a *API) SetIncludesDisabled(disabled bool) {
+	var value int32
+	if disabled {
+		value = 1
+	}
+	atomic.StoreInt32(&a.includesDisabled, value)
+}
+
+// IncludesDisabled checks if the API includes are disabled.
+func (a *API) IncludesDisabled() bool {
+	return atomic.LoadInt32(&a.includesDisabled) == 1
+}
+
+// dropIncludes removes the 'include' parameter from the request query.
+func dropIncludes(req *http.Request) {
+	values := req.URL.Query()
+	values.Del("include")
+	req.URL.RawQuery = values.Encode()
+}
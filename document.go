@@ -0,0 +1,154 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// documentRewriter adjusts the json:api document marshaled by the codec, before it is written to the response.
+type documentRewriter func(doc document) error
+
+// document is a top-level json:api document with its members left undecoded.
+type document map[string]json.RawMessage
+
+// object is a json:api object (i.e. resource object) with its members left undecoded.
+type object map[string]json.RawMessage
+
+// rewriteDocument decodes the document stored in the 'buf', applies all 'rewriters' on it and writes it back.
+func rewriteDocument(buf *bytes.Buffer, rewriters []documentRewriter) error {
+	doc := document{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return err
+	}
+	for _, rewriter := range rewriters {
+		if err := rewriter(doc); err != nil {
+			return err
+		}
+	}
+	buf.Reset()
+	return encodeJSON(buf, doc)
+}
+
+// nullData is the rewriter that sets the primary data to null (i.e. for the empty to-one relationships).
+func nullData(doc document) error {
+	doc["data"] = json.RawMessage("null")
+	return nil
+}
+
+// sortIncluded is the rewriter that sorts the included resources by their type and id,
+// so that the document is stable regardless of the order in which the resources were loaded.
+func sortIncluded(doc document) error {
+	raw, ok := doc["included"]
+	if !ok {
+		return nil
+	}
+	var included []object
+	if err := json.Unmarshal(raw, &included); err != nil {
+		return err
+	}
+	sort.SliceStable(included, func(i, j int) bool {
+		if ti, tj := included[i].resourceType(), included[j].resourceType(); ti != tj {
+			return ti < tj
+		}
+		return included[i].id() < included[j].id()
+	})
+	return doc.set("included", included)
+}
+
+// forEachResource calls 'f' on each primary data resource object and stores the changes back in the document.
+func (d document) forEachResource(f func(resource object) error) error {
+	data, ok := d["data"]
+	if !ok || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	// Primary data is either a single resource object or an array of them.
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		resource := object{}
+		if err := json.Unmarshal(data, &resource); err != nil {
+			return err
+		}
+		if err := f(resource); err != nil {
+			return err
+		}
+		return d.set("data", resource)
+	}
+	var resources []object
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if err := f(resource); err != nil {
+			return err
+		}
+	}
+	return d.set("data", resources)
+}
+
+// set encodes given value and stores it as the document member 'name'.
+func (d document) set(name string, value interface{}) error {
+	raw, err := marshalJSON(value)
+	if err != nil {
+		return err
+	}
+	d[name] = raw
+	return nil
+}
+
+// mergeMember merges the 'values' into the top-level document member 'name' (i.e. 'meta' or 'links').
+func (d document) mergeMember(name string, values map[string]interface{}) error {
+	return object(d).mergeMember(name, values)
+}
+
+// id gets the 'id' member of the resource object.
+func (o object) id() string {
+	var id string
+	_ = json.Unmarshal(o["id"], &id)
+	return id
+}
+
+// resourceType gets the 'type' member of the resource object.
+func (o object) resourceType() string {
+	var t string
+	_ = json.Unmarshal(o["type"], &t)
+	return t
+}
+
+// mergeMember merges the 'values' into the object member 'name' (i.e. 'meta' or 'links').
+func (o object) mergeMember(name string, values map[string]interface{}) error {
+	member := map[string]interface{}{}
+	if raw, ok := o[name]; ok {
+		if err := json.Unmarshal(raw, &member); err != nil {
+			return err
+		}
+	}
+	for k, v := range values {
+		member[k] = v
+	}
+	raw, err := marshalJSON(member)
+	if err != nil {
+		return err
+	}
+	o[name] = raw
+	return nil
+}
+
+// marshalJSON marshals the value without escaping the HTML characters, so that the codec output is not altered.
+func marshalJSON(value interface{}) (json.RawMessage, error) {
+	buf := &bytes.Buffer{}
+	if err := encodeJSON(buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeJSON(buf *bytes.Buffer, value interface{}) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	// Encoder adds a trailing new line.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
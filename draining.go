@@ -0,0 +1,40 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+)
+
+// drainingRetryAfter is the number of seconds after which the client should retry the request refused while draining.
+const drainingRetryAfter = 5
+
+// SetDraining sets the API draining state. While draining, all new requests are refused with the
+// '503 Service Unavailable' status, the requests already being handled are allowed to finish.
+func (a *API) SetDraining(draining bool) {
+	var value int32
+	if draining {
+		value = 1
+	}
+	atomic.StoreInt32(&a.draining, value)
+}
+
+// IsDraining checks if the API is in the draining state.
+func (a *API) IsDraining() bool {
+	return atomic.LoadInt32(&a.draining) == 1
+}
+
+// midDraining is the middleware that refuses new requests while the API is draining.
+func (a *API) midDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !a.IsDraining() {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		log.Debug2f("[%s] %s refused - API is draining", req.Method, req.URL.Path)
+		rw.Header().Set("Retry-After", strconv.Itoa(drainingRetryAfter))
+		a.marshalErrors(rw, req, http.StatusServiceUnavailable, newError(http.StatusServiceUnavailable, "server is shutting down"))
+	})
+}
@@ -0,0 +1,80 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ParamDebugPretty is the query parameter which enables the pretty printed response for a single request,
+// if the AllowDebugPretty option is set.
+const ParamDebugPretty = "debug-pretty"
+
+type debugPrettyCtxKey struct{}
+
+// midDebugPretty is the middleware that marks the request with the ParamDebugPretty parameter to be pretty printed.
+// The parameter is removed from the request query, so that it is not parsed as a json:api parameter.
+func midDebugPretty(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		values := req.URL.Query()
+		if _, ok := values[ParamDebugPretty]; !ok {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		values.Del(ParamDebugPretty)
+		req.URL.RawQuery = values.Encode()
+		next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), debugPrettyCtxKey{}, true)))
+	})
+}
+
+// encodeResponse applies the PrettyPrint and DisableHTMLEscape options on the marshaled response document 'buf'.
+func (a *API) encodeResponse(req *http.Request, buf *bytes.Buffer) error {
+	if a.Options.DisableHTMLEscape {
+		unescaped := unescapeHTML(buf.Bytes())
+		buf.Reset()
+		buf.Write(unescaped)
+	}
+	pretty, _ := req.Context().Value(debugPrettyCtxKey{}).(bool)
+	if !a.Options.PrettyPrint && !pretty {
+		return nil
+	}
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	buf.Reset()
+	_, err := indented.WriteTo(buf)
+	return err
+}
+
+// unescapeHTML replaces the escaped HTML characters '<', '>' and '&' in the JSON 'data' strings with their literals.
+func unescapeHTML(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' || i+1 == len(data) {
+			out = append(out, data[i])
+			continue
+		}
+		if data[i+1] == 'u' && i+6 <= len(data) {
+			switch string(data[i+2 : i+6]) {
+			case "003c":
+				out = append(out, '<')
+				i += 5
+				continue
+			case "003e":
+				out = append(out, '>')
+				i += 5
+				continue
+			case "0026":
+				out = append(out, '&')
+				i += 5
+				continue
+			}
+		}
+		// Keep the escape sequence, so that the escaped backslash is not taken as the escape of the next character.
+		out = append(out, data[i], data[i+1])
+		i++
+	}
+	return out
+}
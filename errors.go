@@ -0,0 +1,141 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+)
+
+// newError creates new json:api error with given http 'status' and 'detail'.
+func newError(status int, detail string) *codec.Error {
+	return &codec.Error{
+		Title:  http.StatusText(status),
+		Status: strconv.Itoa(status),
+		Detail: detail,
+	}
+}
+
+// errGone is the error returned when the requested resource was deleted.
+func errGone() *codec.Error {
+	return newError(http.StatusGone, "requested resource was deleted")
+}
+
+// errResourceNotFound is the error returned when the resource of the 'mStruct' model with given 'id' doesn't exist.
+func errResourceNotFound(mStruct *mapping.ModelStruct, id string) *codec.Error {
+	return newError(http.StatusNotFound, fmt.Sprintf("Resource: '%s' with id: '%s' not found.", mStruct.Collection(), id))
+}
+
+// errInvalidID is the error returned when the url 'id' parameter doesn't match the type of the 'mStruct' primary key.
+func errInvalidID(mStruct *mapping.ModelStruct) *codec.Error {
+	err := newError(http.StatusBadRequest, fmt.Sprintf("id must be %s", primaryKeyTypeName(mStruct)))
+	err.Code = "invalid_id"
+	return err
+}
+
+// errRelatedNotFound is the error returned when the resource with given 'id' referenced by the 'relation' doesn't exist.
+// The codec errors doesn't have the 'source' member, thus the relationship pointer is stored in the error meta.
+func errRelatedNotFound(relation *mapping.StructField, id string) *codec.Error {
+	err := newError(http.StatusUnprocessableEntity, fmt.Sprintf("Related resource: '%s' with id: '%s' not found.", relation.Relationship().RelatedModelStruct().Collection(), id))
+	err.Meta = map[string]interface{}{"pointer": "/data/relationships/" + relation.NeuronName()}
+	return err
+}
+
+// primaryKeyTypeName gets the description of the 'mStruct' primary key type used in the error details.
+func primaryKeyTypeName(mStruct *mapping.ModelStruct) string {
+	t := mStruct.Primary().ReflectField().Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Array:
+		if t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+			return "a UUID"
+		}
+	}
+	return fmt.Sprintf("a valid '%s' value", t.Name())
+}
+
+// errInvalidParameter creates the invalid query parameter error with given 'detail' for the query 'parameter'.
+// The codec errors doesn't have the 'source' member, thus the parameter is stored in the error meta.
+func errInvalidParameter(parameter, detail string) *codec.Error {
+	err := httputil.ErrInvalidQueryParameter()
+	err.Detail = detail
+	err.Meta = map[string]interface{}{"parameter": parameter}
+	return err
+}
+
+// errCommit maps the error returned on the transaction commit. Deferred constraints are checked on commit, thus
+// the unique and integrity violations are the '409 Conflict' and the other violations are the
+// '422 Unprocessable Entity' errors. Any other commit error is an internal error.
+func errCommit(err error) *codec.Error {
+	switch {
+	case errors.Is(err, query.ErrViolationUnique):
+		return newError(http.StatusConflict, "resource violates the unique constraint")
+	case errors.Is(err, query.ErrViolationIntegrityConstraint):
+		return newError(http.StatusConflict, "resource violates the integrity constraint")
+	case errors.Is(err, query.ErrViolationForeignKey), errors.Is(err, query.ErrViolationRestrict):
+		return newError(http.StatusUnprocessableEntity, "resource violates the foreign key constraint")
+	case errors.Is(err, query.ErrViolationNotNull):
+		return newError(http.StatusUnprocessableEntity, "resource violates the not null constraint")
+	case errors.Is(err, query.ErrViolation):
+		return newError(http.StatusUnprocessableEntity, "resource violates the constraint")
+	}
+	return httputil.ErrInternalError()
+}
+
+// errUnmarshal maps the error returned on the payload unmarshal. The malformed JSON body is the '400 Bad Request'
+// error with the syntax error byte offset stored in the error meta, where the well-formed body that is not a valid
+// json:api document is left for the codec errors.
+func errUnmarshal(err error) error {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		cErr := newError(http.StatusBadRequest, "request body is not valid JSON")
+		cErr.Meta = map[string]interface{}{"offset": syntaxErr.Offset}
+		return cErr
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return newError(http.StatusBadRequest, "request body is not valid JSON: unexpected end of input")
+	}
+	return err
+}
+
+// violationErrors maps the multi error of the database layer, composed only of the query violations (i.e. several
+// check constraint violations), into the '422 Unprocessable Entity' json:api errors - one per violation.
+// The neuron violation errors doesn't carry the violated field, thus the errors have no field pointers.
+func violationErrors(err error) ([]*codec.Error, bool) {
+	var multiErr errors.MultiError
+	if !errors.As(err, &multiErr) || len(multiErr) == 0 {
+		return nil, false
+	}
+	errs := make([]*codec.Error, 0, len(multiErr))
+	for _, subErr := range multiErr {
+		if !errors.Is(subErr, query.ErrViolation) {
+			return nil, false
+		}
+		detail := subErr.Error()
+		var detailed *errors.DetailedError
+		if errors.As(subErr, &detailed) && detailed.Details != "" {
+			detail = detailed.Details
+		}
+		errs = append(errs, newError(http.StatusUnprocessableEntity, detail))
+	}
+	return errs, true
+}
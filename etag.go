@@ -0,0 +1,115 @@
+package jsonapi
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// collectionETag computes the weak ETag of the collection of 'models', based on their primary keys and
+// 'updated at' timestamps. The 'extra' values (i.e. total number of resources) are also mixed into the hash.
+// The extra values should contain the canonical request query, so that the responses with different
+// sparse fieldsets or includes have different ETags. The 'salt' is mixed first, so that the change of the
+// representation format (i.e. new deployment) changes the ETags even if the data doesn't.
+func collectionETag(salt string, mStruct *mapping.ModelStruct, models []mapping.Model, extra ...interface{}) (string, error) {
+	h := sha1.New()
+	if salt != "" {
+		fmt.Fprintf(h, "%s;", salt)
+	}
+	updatedAt, hasUpdatedAt := mStruct.UpdatedAt()
+	for _, model := range models {
+		id, err := model.GetPrimaryKeyStringValue()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s;", id)
+		if !hasUpdatedAt {
+			continue
+		}
+		fielder, ok := model.(mapping.Fielder)
+		if !ok {
+			continue
+		}
+		value, err := fielder.GetFieldValue(updatedAt)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%v;", value)
+	}
+	for _, value := range extra {
+		fmt.Fprintf(h, "%v;", value)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
+
+// notModified sets the 'ETag' header and checks if it matches the request 'If-None-Match' header.
+// If it does, the function writes the 'Not Modified' status and returns true.
+func notModified(rw http.ResponseWriter, req *http.Request, etag string) bool {
+	rw.Header().Set("ETag", etag)
+	ifNoneMatch := req.Header.Get("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimSpace(value)
+		// If-None-Match uses the weak comparison.
+		if value == "*" || strings.TrimPrefix(value, "W/") == strings.TrimPrefix(etag, "W/") {
+			rw.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// linkageETag computes the strong ETag of the relationship linkage of the related 'models'. The primary keys are
+// sorted, so that the ETag doesn't depend on the order in which the relationship members were loaded.
+func linkageETag(salt string, models []mapping.Model) (string, error) {
+	var ids []string
+	for _, model := range models {
+		if model == nil || model.IsPrimaryKeyZero() {
+			continue
+		}
+		id, err := model.GetPrimaryKeyStringValue()
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	h := sha1.New()
+	if salt != "" {
+		fmt.Fprintf(h, "%s;", salt)
+	}
+	for _, id := range ids {
+		fmt.Fprintf(h, "%s;", id)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
+
+// checkLinkageIfMatch checks the request 'If-Match' header against the ETag of the current relationship linkage
+// of the related 'models'. If the ETags are not enabled or the header is not provided the check is skipped.
+func (a *API) checkLinkageIfMatch(req *http.Request, models []mapping.Model) error {
+	ifMatch := req.Header.Get("If-Match")
+	if !a.Options.EnableETag || ifMatch == "" {
+		return nil
+	}
+	etag, err := linkageETag(a.Options.ETagSalt, models)
+	if err != nil {
+		log.Errorf("Computing relationship linkage ETag failed: %v", err)
+		return httputil.ErrInternalError()
+	}
+	for _, value := range strings.Split(ifMatch, ",") {
+		// If-Match uses the strong comparison - the weak ETags never match.
+		if value = strings.TrimSpace(value); value == "*" || value == etag {
+			return nil
+		}
+	}
+	return newError(http.StatusPreconditionFailed, "relationship was modified since the provided ETag was computed")
+}
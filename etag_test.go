@@ -0,0 +1,36 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+func TestCollectionETag(t *testing.T) {
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs := c.MustModelStruct(&Blog{})
+	models := []mapping.Model{&Blog{ID: 1}, &Blog{ID: 2}}
+
+	etag := func(query string) string {
+		t.Helper()
+		etag, err := collectionETag("", blogs, models, query)
+		if err != nil {
+			t.Fatalf("computing collection ETag failed: %v", err)
+		}
+		return etag
+	}
+	title, body := etag("fields%5Bblogs%5D=title"), etag("fields%5Bblogs%5D=body")
+	if title == body {
+		t.Errorf("different fieldsets have the same ETag: %s", title)
+	}
+	if again := etag("fields%5Bblogs%5D=title"); again != title {
+		t.Errorf("the same fieldset has different ETags: %s, %s", title, again)
+	}
+	if included := etag("fields%5Bblogs%5D=title&include=author"); included == title {
+		t.Errorf("the included relations don't change the ETag: %s", title)
+	}
+}
@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"fmt"
+
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// checkFilterableFields checks if the 'filters' of the 'mStruct' model are set on the fields allowed by the model's
+// FilterableFielder handler. The primary key is always filterable. The relationship filters are checked against
+// the FilterableFielder of the related model. The 'path' is the relationship path prefix of the filtered fields.
+func (a *API) checkFilterableFields(mStruct *mapping.ModelStruct, filters filter.Filters, path string) error {
+	filterable, isRestricted := a.handlers[mStruct].(FilterableFielder)
+	var fields map[string]struct{}
+	if isRestricted {
+		fields = map[string]struct{}{}
+		for _, name := range filterable.FilterableFields() {
+			fields[name] = struct{}{}
+		}
+	}
+	checkField := func(field *mapping.StructField) error {
+		if !isRestricted || field == mStruct.Primary() {
+			return nil
+		}
+		if _, ok := fields[field.NeuronName()]; !ok {
+			parameter := fmt.Sprintf("filter[%s%s]", path, field.NeuronName())
+			return errInvalidParameter(parameter, fmt.Sprintf("Filtering by the field: '%s%s' is not supported.", path, field.NeuronName()))
+		}
+		return nil
+	}
+	for _, f := range filters {
+		switch ft := f.(type) {
+		case filter.Simple:
+			if err := checkField(ft.StructField); err != nil {
+				return err
+			}
+		case filter.OrGroup:
+			for _, simple := range ft {
+				if err := checkField(simple.StructField); err != nil {
+					return err
+				}
+			}
+		case filter.Relation:
+			if err := checkField(ft.StructField); err != nil {
+				return err
+			}
+			relatedStruct := ft.StructField.Relationship().RelatedModelStruct()
+			if err := a.checkFilterableFields(relatedStruct, ft.Nested, path+ft.StructField.NeuronName()+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package jsonapi
+
+import (
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// foreignKeyLinkageRelations removes the linkage-only includes of the belongs to relations, that were not included
+// by the client, from the neuron 'includes'. The linkage of the returned relations needs to be set from the
+// foreign keys using setForeignKeyLinkage, without an additional query.
+func foreignKeyLinkageRelations(includes, queryIncludes []*query.IncludedRelation) ([]*query.IncludedRelation, []*mapping.StructField) {
+	queried := map[*mapping.StructField]struct{}{}
+	for _, include := range queryIncludes {
+		queried[include.StructField] = struct{}{}
+	}
+	var (
+		result    []*query.IncludedRelation
+		relations []*mapping.StructField
+	)
+	for _, include := range includes {
+		if _, ok := queried[include.StructField]; !ok && include.StructField.Relationship().Kind() == mapping.RelBelongsTo {
+			relations = append(relations, include.StructField)
+			continue
+		}
+		result = append(result, include)
+	}
+	return result, relations
+}
+
+// setForeignKeyLinkage sets the 'relations' models of each model with the primary key stored in its foreign key.
+func setForeignKeyLinkage(models []mapping.Model, relations []*mapping.StructField) error {
+	for _, model := range models {
+		fielder, ok := model.(mapping.Fielder)
+		if !ok {
+			return errors.WrapDetf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement Fielder interface", model)
+		}
+		relationer, ok := model.(mapping.SingleRelationer)
+		if !ok {
+			return errors.WrapDetf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement SingleRelationer interface", model)
+		}
+		for _, relation := range relations {
+			foreignKey := relation.Relationship().ForeignKey()
+			isZero, err := fielder.IsFieldZero(foreignKey)
+			if err != nil {
+				return err
+			}
+			if isZero {
+				continue
+			}
+			value, err := fielder.GetFieldValue(foreignKey)
+			if err != nil {
+				return err
+			}
+			related := mapping.NewModel(relation.Relationship().RelatedModelStruct())
+			if err = related.SetPrimaryKeyValue(value); err != nil {
+				return err
+			}
+			if err = relationer.SetRelationModel(relation, related); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
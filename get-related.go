@@ -12,6 +12,7 @@ import (
 
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/server"
@@ -40,7 +41,7 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 			log.Debugf("[GET-RELATED][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -48,13 +49,13 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 		err := model.SetPrimaryKeyStringValue(id)
 		if err != nil {
 			log.Debugf("[GET-RELATED][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value 'id' parameter"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		relatedScope := query.NewScope(relatedStruct)
@@ -63,39 +64,69 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 		parser, ok := jsonapi.GetCodec(a.Controller).(codec.ParameterParser)
 		if !ok {
 			log.Errorf("jsonapi codec doesn't implement ParameterParser")
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
-		parameters := query.MakeParameters(req.URL.Query())
+		if err := a.checkQueryParameters(req); err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		if err := a.checkIncludes(relatedStruct, req); err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		// The literal 'null' filter values are the 'is null' and 'not null' filters, not the string comparisons.
+		values := req.URL.Query()
+		nullFilters, err := takeNullFilters(relatedStruct, values)
+		if err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		values, err = a.includeFieldSets(relatedStruct, a.resolveFieldsAliases(values))
+		if err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		parameters := query.MakeParameters(values)
 		if err := parser.ParseParameters(a.Controller, relatedScope, parameters); err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
+		for _, nullFilter := range nullFilters {
+			relatedScope.Filter(nullFilter)
+		}
 		if !relationField.IsSlice() {
 			if len(relatedScope.SortingOrder) > 0 {
 				log.Debugf("[GET-RELATED][%s][%s] sorting is not allowed for the GET query type", mStruct, relationField)
 				err := httputil.ErrInvalidQueryParameter()
 				err.Detail = "Sorting is not allowed on GET single queries."
-				a.marshalErrors(rw, 400, err)
+				a.marshalErrors(rw, req, 400, err)
 				return
 			}
 			if relatedScope.Pagination != nil {
 				log.Debugf("[GET-RELATED][%s][%s] pagination is not allowed for the GET query type", mStruct, relationField)
 				err := httputil.ErrInvalidQueryParameter()
 				err.Detail = "Pagination is not allowed on GET single queries."
-				a.marshalErrors(rw, 400, err)
+				a.marshalErrors(rw, req, 400, err)
 				return
 			}
 			if len(relatedScope.Filters) != 0 {
 				log.Debugf("[GET-RELATED][%s][%s] filtering is not allowed for the GET query type", mStruct, relationField)
 				err := httputil.ErrInvalidQueryParameter()
 				err.Detail = "Filtering is not allowed on GET single queries."
-				a.marshalErrors(rw, 400, err)
+				a.marshalErrors(rw, req, 400, err)
 				return
 			}
 		}
 
+		if a.Options.HeaderScopeEnricher != nil {
+			if err := a.Options.HeaderScopeEnricher(req, relatedScope); err != nil {
+				log.Debugf("[GET-RELATED][%s][%s] enriching scope from the headers failed: %v", mStruct, relationField, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+		}
 		// queryIncludes are the included fields from the url query.
 		queryIncludes := relatedScope.IncludedRelations
 		var queryFieldSet mapping.FieldSet
@@ -117,11 +148,11 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 		s := query.NewScope(mStruct, model)
 		if err = s.Include(relationField, neuronFields...); err != nil {
 			log.Errorf("[GET-RELATED][%s][%s] including relation field failed: %v", mStruct, relationField, err)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
-		db := a.DB
+		db := a.readDB()
 		var (
 			isTransactioner bool
 			result          *codec.Payload
@@ -130,7 +161,7 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextGetRelated); ok {
 				if ctx, err = w.GetRelatedWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -138,17 +169,23 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 			var t server.GetRelatedTransactioner
 			if t, isTransactioner = modelHandler.(server.GetRelatedTransactioner); isTransactioner {
 				err = database.RunInTransaction(ctx, db, t.GetRelatedWithTransaction(), func(db database.DB) error {
-					result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relationField)
+					result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relationField, query.GetRelated)
 					return err
 				})
 			}
 		}
 		if !isTransactioner {
-			result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relationField)
+			result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relationField, query.GetRelated)
 		}
 		// execute get relation handler chain.
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if errors.Is(err, query.ErrNoResult) {
+				// The root resource doesn't exist.
+				log.Debug2f("[GET-RELATED][%s][%s] resource: '%s' not found", mStruct, relationField, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -162,7 +199,7 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 		result.IncludedRelations = queryIncludes
 		result.MarshalLinks = codec.LinkOptions{
 			Type:          linkType,
-			BaseURL:       a.Options.PathPrefix,
+			BaseURL:       a.linksBaseURL(),
 			RootID:        id,
 			Collection:    mStruct.Collection(),
 			RelationField: relationField.NeuronName(),
@@ -171,9 +208,7 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 
 		result.PaginationLinks = &codec.PaginationLinks{}
 		sb := strings.Builder{}
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
+		sb.WriteString(a.baseModelPath(mStruct))
 		sb.WriteRune('/')
 		sb.WriteString(id)
 		sb.WriteRune('/')
@@ -183,11 +218,26 @@ func (a *API) handleGetRelated(mStruct *mapping.ModelStruct, relationField *mapp
 			sb.WriteString(q.Encode())
 		}
 		result.PaginationLinks.Self = sb.String()
-		a.marshalPayload(rw, result, http.StatusOK)
+		if result.MarshalSingularFormat && len(result.Data) == 0 {
+			// Absent to-one related resource is marshaled as the 'null' primary data.
+			a.marshalPayload(rw, req, result, http.StatusOK, nullData)
+			return
+		}
+		var rewriters []documentRewriter
+		if a.Options.ExplicitEmptyLinkage {
+			// The related resources are refreshed with their requested relationships, thus the absent linkage has no members.
+			rewriters = append(rewriters, emptyLinkageRewriter(queryFieldSet))
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
 
-func (a *API) getRelationHandleChain(ctx context.Context, db database.DB, s, relatedScope *query.Scope, relationField *mapping.StructField) (*codec.Payload, error) {
+func (a *API) getRelationHandleChain(ctx context.Context, db database.DB, s, relatedScope *query.Scope, relationField *mapping.StructField, method query.Method) (*codec.Payload, error) {
+	if relatedScope != nil {
+		if err := a.prepareScope(ctx, relatedScope, method); err != nil {
+			return nil, err
+		}
+	}
 	modelHandler, hasModelHandler := a.handlers[s.ModelStruct]
 	if hasModelHandler {
 		beforeHandler, ok := modelHandler.(server.BeforeGetRelationHandler)
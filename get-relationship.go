@@ -10,6 +10,7 @@ import (
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/server"
@@ -37,22 +38,22 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 			log.Debugf("[GET-RELATED][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		model := mapping.NewModel(mStruct)
 		err := model.SetPrimaryKeyStringValue(id)
 		if err != nil {
-			log.Debugf("[GET-RELATED][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
-			a.marshalErrors(rw, 0, err)
+			log.Debugf("[GET-RELATIONSHIP][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value 'id' parameter"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -66,14 +67,18 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 			parser, ok := jsonapi.GetCodec(a.Controller).(codec.ParameterParser)
 			if !ok {
 				log.Errorf("jsonapi codec doesn't implement ParameterParser")
-				a.marshalErrors(rw, 500, httputil.ErrInternalError())
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 				return
 			}
 			relatedScope = query.NewScope(relatedModelStruct)
 
-			parameters := query.MakeParameters(req.URL.Query())
+			if err := a.checkQueryParameters(req); err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			parameters := query.MakeParameters(a.resolveFieldsAliases(req.URL.Query()))
 			if err := parser.ParseParameters(a.Controller, relatedScope, parameters); err != nil {
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 			if !relation.IsSlice() {
@@ -81,21 +86,21 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 					log.Debugf("[GET-RELATIONSHIP][%s][%s] sorting is not allowed for the GET query type", mStruct, relation)
 					err := httputil.ErrInvalidQueryParameter()
 					err.Detail = "Sorting is not allowed on GET single queries."
-					a.marshalErrors(rw, 400, err)
+					a.marshalErrors(rw, req, 400, err)
 					return
 				}
 				if relatedScope.Pagination != nil {
 					log.Debugf("[GET-RELATIONSHIP][%s][%s] pagination is not allowed for the GET query type", mStruct, relation)
 					err := httputil.ErrInvalidQueryParameter()
 					err.Detail = "Pagination is not allowed on GET single queries."
-					a.marshalErrors(rw, 400, err)
+					a.marshalErrors(rw, req, 400, err)
 					return
 				}
 				if len(relatedScope.Filters) != 0 {
 					log.Debugf("[GET-RELATIONSHIP][%s][%s] filtering is not allowed for the GET query type", mStruct, relation)
 					err := httputil.ErrInvalidQueryParameter()
 					err.Detail = "Filtering is not allowed on GET single queries."
-					a.marshalErrors(rw, 400, err)
+					a.marshalErrors(rw, req, 400, err)
 					return
 				}
 			}
@@ -103,7 +108,7 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 				log.Debugf("[GET-RELATIONSHIP][%s][%s] field set is not allowed for the GET query type", mStruct, relation)
 				err := httputil.ErrInvalidQueryParameter()
 				err.Detail = "Relationship endpoint fieldset is not allowed on GET single queries."
-				a.marshalErrors(rw, 400, err)
+				a.marshalErrors(rw, req, 400, err)
 				return
 			}
 
@@ -132,11 +137,11 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 		// Include relation.
 		if err = s.Include(relation, relatedModelStruct.Primary()); err != nil {
 			log.Errorf("[GET-RELATIONSHIP][%s][%s] Setting related field into fieldset failed: %v", mStruct.Collection(), relation.NeuronName(), err)
-			a.marshalErrors(rw, 0, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 0, httputil.ErrInternalError())
 			return
 		}
 
-		db := a.DB
+		db := a.readDB()
 		var (
 			isTransactioner bool
 			result          *codec.Payload
@@ -145,7 +150,7 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextGetRelated); ok {
 				if ctx, err = w.GetRelatedWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -153,20 +158,37 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 			var t server.GetRelatedTransactioner
 			if t, isTransactioner = modelHandler.(server.GetRelatedTransactioner); isTransactioner {
 				err = database.RunInTransaction(ctx, db, t.GetRelatedWithTransaction(), func(db database.DB) error {
-					result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relation)
+					result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relation, query.GetRelationship)
 					return err
 				})
 			}
 		}
 		if !isTransactioner {
-			result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relation)
+			result, err = a.getRelationHandleChain(ctx, db, s, relatedScope, relation, query.GetRelationship)
 		}
 		// execute get relation handler chain.
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if errors.Is(err, query.ErrNoResult) {
+				// The root resource doesn't exist.
+				log.Debug2f("[GET-RELATIONSHIP][%s][%s] resource: '%s' not found", mStruct, relation, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
+		if a.Options.EnableETag {
+			// The linkage ETag allows the clients to make the conditional relationship mutations with 'If-Match' header.
+			etag, err := linkageETag(a.Options.ETagSalt, result.Data)
+			if err != nil {
+				log.Errorf("[GET-RELATIONSHIP][%s][%s] computing linkage ETag failed: %v", mStruct, relation, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+			rw.Header().Set("ETag", etag)
+		}
+
 		result.ModelStruct = relatedModelStruct
 		result.IncludedRelations = queryIncludes
 		result.FieldSets = []mapping.FieldSet{{relatedModelStruct.Primary()}}
@@ -177,26 +199,55 @@ func (a *API) handleGetRelationship(mStruct *mapping.ModelStruct, relation *mapp
 		}
 		result.MarshalLinks = codec.LinkOptions{
 			Type:          linkType,
-			BaseURL:       a.Options.PathPrefix,
+			BaseURL:       a.linksBaseURL(),
 			RootID:        id,
 			Collection:    mStruct.Collection(),
 			RelationField: relation.NeuronName(),
 		}
 		result.MarshalSingularFormat = !relation.Relationship().IsToMany()
-		result.PaginationLinks = &codec.PaginationLinks{}
 		sb := strings.Builder{}
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
+		sb.WriteString(a.baseModelPath(mStruct))
 		sb.WriteRune('/')
 		sb.WriteString(id)
-		sb.WriteString("/relationships/")
+		sb.WriteRune('/')
+		related := sb.String() + relation.NeuronName()
+		sb.WriteString("relationships/")
 		sb.WriteString(relation.NeuronName())
+
+		var rewriters []documentRewriter
+		if a.Options.PayloadLinks {
+			// The relationship document links contains both the 'self' and the 'related' link.
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("links", map[string]interface{}{"related": related})
+			})
+		}
+
+		// The to-many relationship identifiers are paginated in memory, and the total count is set in the meta.
+		if relation.Relationship().IsToMany() && relatedScope != nil && relatedScope.Pagination != nil {
+			total := int64(len(result.Data))
+			result.Data = paginateModels(result.Data, relatedScope.Pagination)
+			result.PaginationLinks, err = a.paginationLinks(req, sb.String(), relatedScope.Pagination, total)
+			if err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("meta", map[string]interface{}{"count": total})
+			})
+			a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
+			return
+		}
+
+		result.PaginationLinks = &codec.PaginationLinks{}
 		if q := req.URL.Query(); len(q) > 0 {
 			sb.WriteRune('?')
 			sb.WriteString(q.Encode())
 		}
 		result.PaginationLinks.Self = sb.String()
-		a.marshalPayload(rw, result, http.StatusOK)
+		if result.MarshalSingularFormat && len(result.Data) == 0 {
+			// Absent to-one related resource is marshaled as the 'null' primary data.
+			rewriters = append(rewriters, nullData)
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
@@ -0,0 +1,51 @@
+package jsonapi
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+func TestHandleGetRelationship(t *testing.T) {
+	ta := newTestAPI(t, nil)
+	ta.storeBlogs(&Blog{ID: 1, Title: "title"})
+	ta.repo.store(ta.authors, &Author{ID: 2, Name: "name"})
+
+	t.Run("MissingParent", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs/3/relationships/author", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusNotFound)
+
+		rec = ta.serve(http.MethodGet, "/authors/3/relationships/blogs", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusNotFound)
+	})
+
+	t.Run("EmptyToOne", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs/1/relationships/author", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		if data := decodeDocument(t, rec)["data"]; !bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			t.Errorf("expected null linkage, got: %s", data)
+		}
+	})
+
+	t.Run("EmptyToOneWithQuery", func(t *testing.T) {
+		// The query parameters make the related scope be processed, which must not change the empty result.
+		rec := ta.serve(http.MethodGet, "/blogs/1/relationships/author?include=blogs", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		if data := decodeDocument(t, rec)["data"]; !bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			t.Errorf("expected null linkage, got: %s", data)
+		}
+	})
+
+	t.Run("EmptyToMany", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/authors/2/relationships/blogs", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		if data := decodeDocument(t, rec)["data"]; !bytes.Equal(bytes.TrimSpace(data), []byte("[]")) {
+			t.Errorf("expected empty linkage, got: %s", data)
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 	"github.com/neuronlabs/neuron/server"
 
 	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
@@ -31,7 +32,7 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			log.Errorf("ID value stored in the context is empty.")
 			err := errors.WrapDet(server.ErrURIParameter, "invalid 'id' url parameter").
 				WithDetail("Provided empty ID in query url")
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -39,15 +40,14 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		model := mapping.NewModel(mStruct)
 		if err := model.SetPrimaryKeyStringValue(id); err != nil {
 			log.Debug2f("[GET][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
-			err := errors.WrapDet(server.ErrURIParameter, "invalid query id parameter")
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
 		// Disallow zero value ID.
 		if model.IsPrimaryKeyZero() {
 			err := errors.WrapDet(server.ErrURIParameter, "provided zero value 'id' parameter")
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -58,43 +58,66 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		parser, ok := jsonapi.GetCodec(a.Controller).(codec.ParameterParser)
 		if !ok {
 			log.Errorf("jsonapi codec doesn't implement ParameterParser")
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
-		parameters := query.MakeParameters(req.URL.Query())
+		if err := a.checkQueryParameters(req); err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		if err := a.checkIncludes(mStruct, req); err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		values, err := a.includeFieldSets(mStruct, a.resolveFieldsAliases(req.URL.Query()))
+		if err != nil {
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		parameters := query.MakeParameters(values)
 		if err := parser.ParseParameters(a.Controller, s, parameters); err != nil {
 			log.Debugf("[GET][%s] parsing parameters: '%s' failed: '%v'", mStruct, req.URL.RawQuery, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		if len(s.SortingOrder) > 0 {
 			log.Debugf("[GET][%s] sorting is not allowed for the GET query type", mStruct)
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "Sorting is not allowed on GET single queries."
-			a.marshalErrors(rw, 400, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 		if s.Pagination != nil {
 			log.Debugf("[GET][%s] pagination is not allowed for the GET query type", mStruct)
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "Pagination is not allowed on GET single queries."
-			a.marshalErrors(rw, 400, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 		if len(s.Filters) != 0 {
 			log.Debugf("[GET][%s] filtering is not allowed for the GET query type", mStruct)
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "Filtering is not allowed on GET single queries."
-			a.marshalErrors(rw, 400, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
+		if a.Options.HeaderScopeEnricher != nil {
+			if err := a.Options.HeaderScopeEnricher(req, s); err != nil {
+				log.Debugf("[GET][%s] enriching scope from the headers failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+		}
 		// queryIncludes are the included fields from the url query.
 		queryIncludes := s.IncludedRelations
 		var queryFieldSet mapping.FieldSet
 		var fields mapping.FieldSet
-		if len(s.FieldSets) == 0 {
+		if defaultFields, ok := a.getFields[mStruct]; ok && len(s.FieldSets) == 0 {
+			fields = append(mapping.FieldSet{}, defaultFields...)
+			queryFieldSet = fields
+		} else if len(s.FieldSets) == 0 {
 			fields = append(s.ModelStruct.Attributes(), s.ModelStruct.RelationFields()...)
 			queryFieldSet = fields
 		} else {
@@ -104,25 +127,38 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		// json:api fieldset is a combination of fields + relations.
 		// The same situation is with includes.
 		neuronFields, neuronIncludes := parseFieldSetAndIncludes(mStruct, fields, queryIncludes)
+		// The locale is needed for the 'Content-Language' header.
+		localeField, hasLocale := a.localeField(mStruct)
+		if hasLocale && !neuronFields.Contains(localeField) {
+			neuronFields = append(neuronFields, localeField)
+		}
 		s.FieldSets = []mapping.FieldSet{neuronFields}
+		var foreignKeyRelations []*mapping.StructField
+		if a.Options.ForeignKeyLinkage {
+			neuronIncludes, foreignKeyRelations = foreignKeyLinkageRelations(neuronIncludes, queryIncludes)
+		}
 		s.IncludedRelations = neuronIncludes
 
 		ctx := req.Context()
-		db := a.DB
+		db := a.readDB()
 		var (
 			result          *codec.Payload
 			isTransactioner bool
-			err             error
 		)
 		modelHandler, hasModelHandler := a.handlers[mStruct]
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextGetter); ok {
 				ctx, err = w.GetWithContext(ctx)
 				if err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
+			// The default filters and the scope preparer are applied only to the read endpoint scopes.
+			if err = a.prepareScope(ctx, s, query.Get); err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
 
 			var t server.GetTransactioner
 			if t, isTransactioner = modelHandler.(server.GetTransactioner); isTransactioner {
@@ -138,10 +174,29 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		}
 		if err != nil {
 			log.Debugf("[GET][%s] getting result failed: %v", mStruct, err)
-			a.marshalErrors(rw, 0, err)
+			if a.Options.ReturnGoneForDeleted && errors.Is(err, query.ErrNoResult) && a.isSoftDeleted(ctx, db, mStruct, model) {
+				a.marshalErrors(rw, req, http.StatusGone, errGone())
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
+		if hasLocale {
+			if err = setContentLanguage(rw, localeField, result.Data); err != nil {
+				log.Errorf("[GET][%s] getting resource locale failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+		}
+		if len(foreignKeyRelations) > 0 {
+			if err = setForeignKeyLinkage(result.Data, foreignKeyRelations); err != nil {
+				log.Errorf("[GET][%s] setting foreign key linkage failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+		}
+
 		linkType := codec.ResourceLink
 		// but if the config doesn't allow that - set 'jsonapi.NoLink'
 		if !a.Options.PayloadLinks {
@@ -156,7 +211,7 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if result.MarshalLinks.Type == codec.NoLink {
 			result.MarshalLinks = codec.LinkOptions{
 				Type:       linkType,
-				BaseURL:    a.Options.PathPrefix,
+				BaseURL:    a.linksBaseURL(),
 				RootID:     id,
 				Collection: mStruct.Collection(),
 			}
@@ -164,9 +219,7 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		result.MarshalSingularFormat = true
 		result.PaginationLinks = &codec.PaginationLinks{}
 		sb := strings.Builder{}
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
+		sb.WriteString(a.baseModelPath(mStruct))
 		sb.WriteRune('/')
 		sb.WriteString(id)
 		if q := req.URL.Query(); len(q) > 0 {
@@ -174,7 +227,27 @@ func (a *API) handleGet(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			sb.WriteString(q.Encode())
 		}
 		result.PaginationLinks.Self = sb.String()
-		a.marshalPayload(rw, result, http.StatusOK)
+
+		var rewriters []documentRewriter
+		if provider, ok := modelHandler.(ResourceMetaProvider); ok {
+			rewriter, err := resourceMetaRewriter(ctx, provider, result.Data)
+			if err != nil {
+				log.Debugf("[GET][%s] getting resource meta failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			rewriters = append(rewriters, rewriter)
+		}
+		if rewriter, ok := nullLinkageRewriter(queryFieldSet); ok {
+			rewriters = append(rewriters, rewriter)
+		}
+		if a.Options.ExplicitEmptyLinkage {
+			rewriters = append(rewriters, emptyLinkageRewriter(queryFieldSet))
+		}
+		if a.Options.DirectRelatedLinks {
+			rewriters = append(rewriters, directRelatedLinksRewriter(a.Options.PathPrefix))
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
 
@@ -208,3 +281,21 @@ func (a *API) getHandleChain(ctx context.Context, db database.DB, q *query.Scope
 	}
 	return result, err
 }
+
+// isSoftDeleted checks if the 'model' exists in the database with its 'deleted at' timestamp set.
+func (a *API) isSoftDeleted(ctx context.Context, db database.DB, mStruct *mapping.ModelStruct, model mapping.Model) bool {
+	deletedAt, ok := mStruct.DeletedAt()
+	if !ok {
+		return false
+	}
+	// The explicit filter on the 'deleted at' field makes the query include soft-deleted rows.
+	s := query.NewScope(mStruct)
+	s.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
+	s.Filter(filter.New(deletedAt, filter.OpNotNull))
+	count, err := database.Count(ctx, db, s)
+	if err != nil {
+		log.Debugf("[GET][%s] checking if the model is soft deleted failed: %v", mStruct, err)
+		return false
+	}
+	return count > 0
+}
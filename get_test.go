@@ -0,0 +1,131 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+func TestHandleGetUnsetBelongsTo(t *testing.T) {
+	ta := newTestAPI(t, nil)
+	ta.storeBlogs(&Blog{ID: 1, Title: "title"}, &Blog{ID: 2, Title: "title", AuthorID: 3})
+	author, ok := ta.blogs.RelationByName("Author")
+	if !ok {
+		t.Fatal("no author relationship")
+	}
+	name := author.NeuronName()
+
+	t.Run("Get", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs/1", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		if data, ok := relationshipData(t, primaryResource(t, decodeDocument(t, rec)), name); !ok || string(data) != "null" {
+			t.Errorf("expected null linkage, got: %s", data)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		var resources []object
+		if err := json.Unmarshal(decodeDocument(t, rec)["data"], &resources); err != nil {
+			t.Fatalf("decoding primary data failed: %v", err)
+		}
+		for _, resource := range resources {
+			data, ok := relationshipData(t, resource, name)
+			if !ok {
+				t.Fatalf("missing linkage of resource: %s", resource.id())
+			}
+			if unset := resource.id() == "1"; unset != (string(data) == "null") {
+				t.Errorf("unexpected linkage of resource: %s - %s", resource.id(), data)
+			}
+		}
+	})
+}
+
+// visibleBlogs is the blog model handler with the default filter on the 'visible' title. It records the methods
+// of the prepared scopes.
+type visibleBlogs struct {
+	methods []query.Method
+}
+
+// DefaultFilters implements DefaultScoper interface.
+func (v *visibleBlogs) DefaultFilters(_ context.Context, s *query.Scope) ([]filter.Filter, error) {
+	title, _ := s.ModelStruct.Attribute("title")
+	return []filter.Filter{filter.New(title, filter.OpEqual, "visible")}, nil
+}
+
+// PrepareScope implements ScopePreparer interface.
+func (v *visibleBlogs) PrepareScope(_ context.Context, _ *query.Scope, method query.Method) error {
+	v.methods = append(v.methods, method)
+	return nil
+}
+
+func TestDefaultScope(t *testing.T) {
+	newScopedAPI := func(t *testing.T) (*testAPI, *visibleBlogs) {
+		handler := &visibleBlogs{}
+		ta := newTestAPI(t, nil, WithModelHandler(&Blog{}, handler))
+		ta.storeBlogs(&Blog{ID: 1, Title: "visible", AuthorID: 2}, &Blog{ID: 2, Title: "hidden", AuthorID: 2})
+		return ta, handler
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		ta, handler := newScopedAPI(t)
+		rec := ta.serve(http.MethodGet, "/blogs/1", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		rec = ta.serve(http.MethodGet, "/blogs/2", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusNotFound)
+
+		if len(handler.methods) != 2 || handler.methods[0] != query.Get {
+			t.Errorf("unexpected prepared scope methods: %v", handler.methods)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		ta, _ := newScopedAPI(t)
+		rec := ta.serve(http.MethodGet, "/blogs", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		var resources []object
+		if err := json.Unmarshal(decodeDocument(t, rec)["data"], &resources); err != nil {
+			t.Fatalf("decoding primary data failed: %v", err)
+		}
+		if len(resources) != 1 || resources[0].id() != "1" {
+			t.Errorf("expected only the visible blog, got: %v", resources)
+		}
+	})
+
+	t.Run("UpdateOutOfScope", func(t *testing.T) {
+		ta, handler := newScopedAPI(t)
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"title":"hidden"}}}`,
+			"Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		if blog := ta.storedBlog(t, 1); blog.Title != "hidden" {
+			t.Errorf("expected title: 'hidden', got: '%s'", blog.Title)
+		}
+		if len(handler.methods) != 0 {
+			t.Errorf("update scopes prepared as: %v", handler.methods)
+		}
+	})
+
+	t.Run("UpdateRelationshipOutOfScope", func(t *testing.T) {
+		ta, _ := newScopedAPI(t)
+		rec := ta.serve(http.MethodPatch, "/blogs/2/relationships/author", `{"data":null}`)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusNoContent {
+			t.Fatalf("unexpected status: %d, body: %s", rec.Code, rec.Body.String())
+		}
+
+		if blog := ta.storedBlog(t, 2); blog.AuthorID != 0 {
+			t.Errorf("expected blog without author, got author id: %d", blog.AuthorID)
+		}
+	})
+}
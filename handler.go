@@ -152,13 +152,30 @@ func (d *DefaultHandler) HandleUpdate(ctx context.Context, db database.DB, input
 		}
 	}
 
-	// update the model.
-	if _, err = db.Update(ctx, input.ModelStruct, model); err != nil {
+	// Update only the fields provided in the input fieldset - omitted fields are left untouched.
+	var updated int64
+	if updater, ok := db.(database.QueryUpdater); ok {
+		q := query.NewScope(input.ModelStruct, model)
+		q.FieldSets = input.FieldSets
+		updated, err = updater.UpdateQuery(ctx, q)
+	} else {
+		// The selected fields are updated even with zero values, so that the fields explicitly set to null are cleared.
+		log.Debug2f("DB: %T doesn't implement QueryUpdater interface - updating with the query builder", db)
+		updated, err = db.QueryCtx(ctx, input.ModelStruct, model).Select(input.FieldSets[0]...).Update()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		// The resource doesn't exist - no need to continue with the relations and the refetch.
+		err = errors.WrapDetf(query.ErrNoResult, "nothing to update")
 		return nil, err
 	}
+	// The number of updated rows is returned in the meta, so that the clients could confirm the update took effect.
+	meta := codec.Meta{"updated": updated}
 
 	if len(input.IncludedRelations) == 0 {
-		return &codec.Payload{Data: []mapping.Model{model}}, nil
+		return &codec.Payload{Data: []mapping.Model{model}, Meta: meta}, nil
 	}
 	for _, relation := range input.IncludedRelations {
 		switch relation.StructField.Relationship().Kind() {
@@ -200,7 +217,7 @@ func (d *DefaultHandler) HandleUpdate(ctx context.Context, db database.DB, input
 			return nil, err
 		}
 	}
-	return &codec.Payload{Data: []mapping.Model{model}}, nil
+	return &codec.Payload{Data: []mapping.Model{model}, Meta: meta}, nil
 }
 
 // HandleGet implements api.GetHandler interface.
@@ -262,7 +279,8 @@ func (d *DefaultHandler) HandleGetRelation(ctx context.Context, db database.DB,
 		if err != nil {
 			return nil, err
 		}
-		if relatedModel != nil {
+		// The related model with zero primary key is not set.
+		if relatedModel != nil && !relatedModel.IsPrimaryKeyZero() {
 			relatedModels = []mapping.Model{relatedModel}
 		}
 	default:
@@ -319,7 +337,8 @@ func (d *DefaultHandler) HandleGetRelationship(ctx context.Context, params serve
 		if err != nil {
 			return nil, err
 		}
-		if relatedModel != nil {
+		// The related model with zero primary key is not set.
+		if relatedModel != nil && !relatedModel.IsPrimaryKeyZero() {
 			payload.Data = []mapping.Model{relatedModel}
 		}
 	default:
@@ -0,0 +1,51 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// includeFieldSets resolves the contradiction between the 'include' and the sparse fieldset 'fields[type]' parameters
+// of the 'mStruct' model query 'values'. If a relationship of the include path is excluded by the fieldset of its
+// model, the relationship is added to the fieldset - the include wins, so that the related resources are both linked
+// in the relationships and present in the included. If the StrictIncludeFields option is set, such request is
+// rejected with the '400 Bad Request' status instead.
+func (a *API) includeFieldSets(mStruct *mapping.ModelStruct, values url.Values) (url.Values, error) {
+	for _, value := range values["include"] {
+		for _, includePath := range strings.Split(value, ",") {
+			model := mStruct
+			for _, segment := range strings.Split(includePath, ".") {
+				relation, ok := model.RelationByName(segment)
+				if !ok {
+					break
+				}
+				key := "fields[" + model.Collection() + "]"
+				if fields, ok := values[key]; ok && !containsField(fields, relation.NeuronName()) {
+					if a.Options.StrictIncludeFields {
+						log.Debug2f("Included relation: '%s' is excluded by the fieldset: '%s'", includePath, key)
+						return nil, errInvalidParameter(key, fmt.Sprintf("Included relationship: '%s' is excluded by the fieldset: '%s'.", relation.NeuronName(), key))
+					}
+					values[key] = []string{strings.Join(append(fields, relation.NeuronName()), ",")}
+				}
+				model = relation.Relationship().RelatedModelStruct()
+			}
+		}
+	}
+	return values, nil
+}
+
+// containsField checks if the comma separated fieldset 'values' contains the field with given 'name'.
+func containsField(values []string, name string) bool {
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			if field == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/controller"
+)
+
+func TestIncludeFieldSets(t *testing.T) {
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs := c.MustModelStruct(&Blog{})
+	authors := c.MustModelStruct(&Author{})
+	author, ok := blogs.RelationByName("Author")
+	if !ok {
+		t.Fatal("no author relationship")
+	}
+	authorBlogs, ok := authors.RelationByName("Blogs")
+	if !ok {
+		t.Fatal("no blogs relationship")
+	}
+	title := blogs.MustFieldByName("Title").NeuronName()
+	name := authors.MustFieldByName("Name").NeuronName()
+
+	t.Run("IncludeWins", func(t *testing.T) {
+		a := New()
+		values, err := a.includeFieldSets(blogs, url.Values{
+			"include":         {author.NeuronName() + "." + authorBlogs.NeuronName()},
+			"fields[blogs]":   {title},
+			"fields[authors]": {name},
+		})
+		if err != nil {
+			t.Fatalf("resolving fieldsets failed: %v", err)
+		}
+		if fields := values["fields[blogs]"]; len(fields) != 1 || fields[0] != title+","+author.NeuronName() {
+			t.Errorf("included relationship not added to the fieldset: %v", fields)
+		}
+		if fields := values["fields[authors]"]; len(fields) != 1 || fields[0] != name+","+authorBlogs.NeuronName() {
+			t.Errorf("nested included relationship not added to the fieldset: %v", fields)
+		}
+	})
+
+	t.Run("NoContradiction", func(t *testing.T) {
+		a := New(WithStrictIncludeFields())
+		for _, values := range []url.Values{
+			{"include": {author.NeuronName()}},
+			{"include": {author.NeuronName()}, "fields[blogs]": {title + "," + author.NeuronName()}},
+			{"include": {author.NeuronName()}, "fields[authors]": {name}},
+		} {
+			expected := url.Values{}
+			for key, value := range values {
+				expected[key] = append([]string{}, value...)
+			}
+			result, err := a.includeFieldSets(blogs, values)
+			if err != nil {
+				t.Fatalf("%v: resolving fieldsets failed: %v", values, err)
+			}
+			for key, value := range expected {
+				if len(result[key]) != 1 || result[key][0] != value[0] {
+					t.Errorf("%v: parameter: '%s' changed: %v", expected, key, result[key])
+				}
+			}
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		a := New(WithStrictIncludeFields())
+		_, err := a.includeFieldSets(blogs, url.Values{"include": {author.NeuronName()}, "fields[blogs]": {title}})
+		if err == nil {
+			t.Fatal("expected error for the included relationship excluded by the fieldset")
+		}
+		cErr, ok := err.(*codec.Error)
+		if !ok {
+			t.Fatalf("expected codec error, got: %T", err)
+		}
+		if cErr.Status != "400" {
+			t.Errorf("expected status: 400, got: %s", cErr.Status)
+		}
+		if cErr.Meta["parameter"] != "fields[blogs]" {
+			t.Errorf("expected parameter: 'fields[blogs]' in the error meta, got: %v", cErr.Meta["parameter"])
+		}
+	})
+}
+
+func TestHandleIncludeExcludedByFieldSet(t *testing.T) {
+	ta := newTestAPI(t, nil)
+	ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body", AuthorID: 2})
+
+	// The include wins - the relationship is both linked and included, and the attributes are limited by the fieldset.
+	expectIncluded := func(t *testing.T, doc document, resource object) {
+		t.Helper()
+		if data, _ := relationshipData(t, resource, "author"); string(data) != `{"type":"authors","id":"2"}` {
+			t.Errorf("expected author linkage, got: %s", data)
+		}
+		var included []object
+		if err := json.Unmarshal(doc["included"], &included); err != nil {
+			t.Fatalf("decoding included failed: %v", err)
+		}
+		if len(included) != 1 || included[0].resourceType() != "authors" || included[0].id() != "2" {
+			t.Errorf("expected included author, got: %v", included)
+		}
+		attributes := map[string]interface{}{}
+		if err := json.Unmarshal(resource["attributes"], &attributes); err != nil {
+			t.Fatalf("decoding attributes failed: %v", err)
+		}
+		if _, ok := attributes["body"]; ok {
+			t.Error("attribute excluded by the fieldset is marshaled")
+		}
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs/1?include=author&fields[blogs]=title", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		doc := decodeDocument(t, rec)
+		expectIncluded(t, doc, primaryResource(t, doc))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rec := ta.serve(http.MethodGet, "/blogs?include=author&fields[blogs]=title", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		doc := decodeDocument(t, rec)
+		var resources []object
+		if err := json.Unmarshal(doc["data"], &resources); err != nil {
+			t.Fatalf("decoding primary data failed: %v", err)
+		}
+		if len(resources) != 1 {
+			t.Fatalf("expected single resource, got: %d", len(resources))
+		}
+		expectIncluded(t, doc, resources[0])
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithStrictIncludeFields())
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2})
+
+		rec := ta.serve(http.MethodGet, "/blogs/1?include=author&fields[blogs]=title", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusBadRequest)
+		rec = ta.serve(http.MethodGet, "/blogs?include=author&fields[blogs]=title", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,61 @@
+package jsonapi
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+)
+
+// indexResourceType is the resource type of the collections listed in the index document.
+const indexResourceType = "collections"
+
+func (a *API) setIndexRoute(router *httprouter.Router) {
+	endpointPath := a.basePath()
+	chain := append(a.middlewares(), MidAccept)
+	log.Debugf("GET %s", endpointPath)
+	router.GET(endpointPath, httputil.Wrap(chain.Handle(a.handleIndex())))
+}
+
+// handleIndex creates the handler that lists all the collections registered in the API, with the links
+// to their endpoints. The collection aliases are listed in the resource meta.
+func (a *API) handleIndex() http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		data := []map[string]interface{}{}
+		for mStruct := range a.models {
+			resource := map[string]interface{}{
+				"type":  indexResourceType,
+				"id":    mStruct.Collection(),
+				"links": map[string]interface{}{"self": a.baseModelPath(mStruct)},
+			}
+			if aliases := a.aliases[mStruct]; len(aliases) > 0 {
+				resource["meta"] = map[string]interface{}{"aliases": aliases}
+			}
+			data = append(data, resource)
+		}
+		sort.Slice(data, func(i, j int) bool {
+			return data[i]["id"].(string) < data[j]["id"].(string)
+		})
+
+		buf := &bytes.Buffer{}
+		doc := map[string]interface{}{
+			"data":  data,
+			"links": map[string]interface{}{"self": path.Clean(a.basePath())},
+		}
+		if err := encodeJSON(buf, doc); err != nil {
+			log.Errorf("Marshaling index document failed: %v", err)
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+			return
+		}
+		a.writeContentType(rw, CtxProfiles(req.Context())...)
+		rw.WriteHeader(http.StatusOK)
+		if _, err := rw.Write(buf.Bytes()); err != nil {
+			log.Errorf("Writing to response writer failed: %v", err)
+		}
+	}
+}
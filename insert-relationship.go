@@ -9,6 +9,7 @@ import (
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/query/filter"
@@ -36,23 +37,27 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			log.Debugf("[INSERT-RELATIONSHIP][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		model := mapping.NewModel(mStruct)
 		if err := model.SetPrimaryKeyStringValue(id); err != nil {
 			log.Debugf("[INSERT-RELATIONSHIP][%s] Setting string primary key: %s failed: %v", mStruct, id, err)
-			err := httputil.ErrInvalidQueryParameter()
-			err.Detail = "provided invalid 'id' in the query parameter."
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value primary key"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+
+		if err := a.checkRelationshipBody(req); err != nil {
+			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] empty request body", mStruct, relation)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
@@ -64,14 +69,20 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		})
 		if err != nil {
 			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] unmarshaling payload failed: %v", mStruct, relation, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errUnmarshal(err))
 			return
 		}
 		if relation.Kind() == mapping.KindRelationshipSingle && len(payload.Data) > 1 {
 			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] to-one relationship has more than one input", mStruct, relation)
 			err := httputil.ErrInvalidInput()
 			err.Detail = "cannot set many relationships for a to-one relationship"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+
+		if err := a.checkRelationshipMembers(payload); err != nil {
+			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
@@ -80,7 +91,7 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			if relation.IsPrimaryKeyZero() {
 				err := httputil.ErrInvalidJSONFieldValue()
 				err.Detail = "one of provided relationships doesn't have it's primary key value stored"
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 		}
@@ -91,13 +102,14 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		}
 
 		s := query.NewScope(mStruct)
-		s.FieldSets = payload.FieldSets
+		// Get only the primary key with the current relation members.
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary()}}
 		s.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
 
 		// Include relation values.
 		if err = s.Include(relation, relation.Relationship().RelatedModelStruct().Primary()); err != nil {
 			log.Errorf("[INSERT-RELATIONSHIP][%s][%s] including relation with it's primary key failed: %v", mStruct, relation, err)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
@@ -106,7 +118,7 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextInsertRelationer); ok {
 				if ctx, err = w.InsertRelationsWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -116,7 +128,7 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		tx, err := database.Begin(ctx, a.DB, nil)
 		if err != nil {
 			log.Errorf("[INSERT-RELATIONSHIP][%s][%s] begin transaction failed: %v", mStruct, relation, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		defer func() {
@@ -127,17 +139,28 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			}
 		}()
 
-		_, err = a.getHandleChain(ctx, tx, s)
+		var current *codec.Payload
+		current, err = a.getHandleChain(ctx, tx, s)
+		if err == nil && len(current.Data) == 0 {
+			err = errors.WrapDetf(query.ErrNoResult, "resource not found")
+		}
 		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				log.Debug2f("[INSERT-RELATIONSHIP][%s][%s] resource: '%s' not found", mStruct, relation, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
 			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] getting model with included relationship failed: %v", mStruct, relation, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
+		// The current relation members are taken from the stored model.
+		model = current.Data[0]
 
 		if hasModelHandler {
 			if beforeHandler, ok := modelHandler.(server.BeforeInsertRelationsHandler); ok {
 				if err = beforeHandler.HandleBeforeInsertRelations(ctx, tx, model, payload); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -150,14 +173,14 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			if !ok {
 				log.Errorf("[INSERT-RELATIONSHIP][%s][%s] model doesn't implement MultiRelationer interface", mStruct, relation)
 				err = httputil.ErrInternalError()
-				a.marshalErrors(rw, 500, httputil.ErrInternalError())
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 				return
 			}
 			var models []mapping.Model
 			models, err = mr.GetRelationModels(relation)
 			if err != nil {
 				log.Errorf("[INSERT-RELATIONSHIP][%s][%s] getting MultiRelationer relations failed: %v", mStruct, relation, err)
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 			for _, relationModel := range models {
@@ -170,14 +193,14 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			if !ok {
 				log.Errorf("[INSERT-RELATIONSHIP][%s][%s] model doesn't implement SingleRelationer interface", mStruct, relation)
 				err = httputil.ErrInternalError()
-				a.marshalErrors(rw, 500, httputil.ErrInternalError())
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 				return
 			}
 			var relationModel mapping.Model
 			relationModel, err = sr.GetRelationModel(relation)
 			if err != nil {
 				log.Errorf("[INSERT-RELATIONSHIP][%s][%s] getting SingleRelationer models failed: %v", mStruct, relation, err)
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 			if relationModel != nil {
@@ -185,9 +208,32 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 			}
 		}
 
+		if err = a.checkLinkageIfMatch(req, relationModels); err != nil {
+			log.Debugf("[INSERT-RELATIONSHIP][%s][%s] If-Match precondition failed: %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+
+		// A to-one relationship could have only a single member - inserting other member conflicts with the current one,
+		// unless the ReplaceToOneRelationship option is set.
+		var replaceToOne bool
+		if relation.Kind() == mapping.KindRelationshipSingle && len(relationModels) == 1 &&
+			relationModels[0].GetPrimaryKeyHashableValue() != payload.Data[0].GetPrimaryKeyHashableValue() {
+			if !a.Options.ReplaceToOneRelationship {
+				log.Debugf("[INSERT-RELATIONSHIP][%s][%s] to-one relationship already exists", mStruct, relation)
+				err = newError(http.StatusConflict, "to-one relationship already exists")
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			replaceToOne = true
+		}
+
 		// Get the set of (current relations) - (to delete relations)  -> relations to set.
 		idMap := map[interface{}]int{}
 		relationsToSet := relationModels
+		if replaceToOne {
+			relationsToSet = nil
+		}
 		for i, current := range relationModels {
 			idMap[current.GetPrimaryKeyHashableValue()] = i
 		}
@@ -201,9 +247,11 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		}
 
 		// If nothing is being deleted - json:api specify that this is successful request - and return no content status.
-		if len(relationsToSet) == len(relationModels) {
+		if !replaceToOne && len(relationsToSet) == len(relationModels) {
 			if err = tx.Commit(); err != nil {
 				log.Errorf("Committing transaction failed: %v", err)
+				a.marshalErrors(rw, req, 0, errCommit(err))
+				return
 			}
 			rw.WriteHeader(http.StatusNoContent)
 			return
@@ -218,13 +266,13 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		result, err = handler.HandleSetRelations(ctx, tx, model, relationsToSet, relation)
 		if err != nil {
 			log.Debugf("[INSERT-RELATIONSHIPS][%s][%S] HandleSetRelations failed: %v", err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		if hasModelHandler {
 			if afterHandler, ok := modelHandler.(server.AfterInsertRelationsHandler); ok {
 				if err = afterHandler.HandleAfterInsertRelations(ctx, tx, model, relationsToSet, result); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -232,7 +280,7 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 
 		if err = tx.Commit(); err != nil {
 			log.Errorf("Committing transaction failed: %v", err)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 0, errCommit(err))
 			return
 		}
 		var hasJsonapiMimeType bool
@@ -256,12 +304,22 @@ func (a *API) handleInsertRelationship(mStruct *mapping.ModelStruct, relation *m
 		result.FieldSets = []mapping.FieldSet{{relation.Relationship().RelatedModelStruct().Primary()}}
 		result.MarshalLinks = codec.LinkOptions{
 			Type:          link,
-			BaseURL:       a.Options.PathPrefix,
+			BaseURL:       a.linksBaseURL(),
 			RootID:        id,
 			Collection:    mStruct.Collection(),
 			RelationField: relation.NeuronName(),
 		}
 		result.MarshalSingularFormat = relation.Kind() == mapping.KindRelationshipSingle
-		a.marshalPayload(rw, result, http.StatusOK)
+		var rewriters []documentRewriter
+		if a.prefersPreviousLinkage(req) {
+			rewriter, err := previousLinkageRewriter(relation, relationModels)
+			if err != nil {
+				log.Errorf("[INSERT-RELATIONSHIP][%s][%s] getting previous relationship linkage failed: %v", mStruct, relation, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+			rewriters = append(rewriters, rewriter)
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
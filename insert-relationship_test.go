@@ -0,0 +1,64 @@
+package jsonapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHandleInsertToOneRelationship(t *testing.T) {
+	t.Run("Occupied", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 3})
+
+		rec := ta.serve(http.MethodPost, "/blogs/1/relationships/author", `{"data":{"type":"authors","id":"3"}}`)
+		expectStatus(t, rec, http.StatusConflict)
+
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 2 {
+			t.Errorf("expected author id: 2, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("SameMember", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2})
+
+		rec := ta.serve(http.MethodPost, "/blogs/1/relationships/author", `{"data":{"type":"authors","id":"2"}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 2 {
+			t.Errorf("expected author id: 2, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1}, &Blog{ID: 2, AuthorID: 3})
+
+		rec := ta.serve(http.MethodPost, "/blogs/1/relationships/author", `{"data":{"type":"authors","id":"3"}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 3 {
+			t.Errorf("expected author id: 3, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithReplaceToOneRelationship())
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 3})
+
+		rec := ta.serve(http.MethodPost, "/blogs/1/relationships/author", `{"data":{"type":"authors","id":"3"}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 3 {
+			t.Errorf("expected author id: 3, got: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("MissingParent", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 2, AuthorID: 3})
+
+		rec := ta.serve(http.MethodPost, "/blogs/1/relationships/author", `{"data":{"type":"authors","id":"3"}}`)
+		expectStatus(t, rec, http.StatusNotFound)
+	})
+}
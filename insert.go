@@ -9,8 +9,10 @@ import (
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 	"github.com/neuronlabs/neuron/server"
 )
 
@@ -28,7 +30,7 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		payload, err := pu.UnmarshalPayload(req.Body, codec.UnmarshalOptions{StrictUnmarshal: a.Options.StrictUnmarshal, ModelStruct: mStruct})
 		if err != nil {
 			log.Debugf("Unmarshal scope for: '%s' failed: %v", mStruct.Collection(), err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errUnmarshal(err))
 			return
 		}
 
@@ -36,13 +38,13 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		case 0:
 			err := httputil.ErrInvalidInput()
 			err.Detail = "nothing to insert"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		case 1:
 		default:
 			err := httputil.ErrInvalidInput()
 			err.Detail = "bulk insert not implemented yet."
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		model := payload.Data[0]
@@ -51,11 +53,14 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if len(payload.FieldSets) != 1 {
 			err := httputil.ErrInvalidInput()
 			err.Detail = "bulk inserted not implemented yet"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
-		var selectedPrimary bool
+		var (
+			selectedPrimary bool
+			belongsTo       []*mapping.StructField
+		)
 		fields := mapping.FieldSet{}
 		for _, field := range payload.FieldSets[0] {
 			switch field.Kind() {
@@ -64,34 +69,42 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 					relationer, ok := model.(mapping.SingleRelationer)
 					if !ok {
 						log.Errorf("Model: '%s' doesn't implement mapping.SingleRelationer interface", mStruct.Collection())
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 						return
 					}
 					relation, err := relationer.GetRelationModel(field)
 					if err != nil {
 						log.Errorf("Getting relation model failed: %v", err)
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 						return
 					}
-					if relation.IsPrimaryKeyZero() {
-						a.marshalErrors(rw, http.StatusBadRequest, httputil.ErrInvalidQueryParameter())
+					if relation != nil && relation.IsPrimaryKeyZero() {
+						a.marshalErrors(rw, req, http.StatusBadRequest, httputil.ErrInvalidQueryParameter())
 						return
 					}
 
 					fielder, ok := model.(mapping.Fielder)
 					if !ok {
 						log.Errorf("Model: '%s' doesn't implement mapping.Fielder interface", mStruct.Collection())
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+						return
 					}
 					foreignKey := field.Relationship().ForeignKey()
-					if err = fielder.SetFieldValue(foreignKey, relation.GetPrimaryKeyValue()); err != nil {
+					if relation == nil {
+						// Explicit null relationship data leaves the foreign key unset.
+						err = fielder.SetFieldZeroValue(foreignKey)
+					} else {
+						err = fielder.SetFieldValue(foreignKey, relation.GetPrimaryKeyValue())
+					}
+					if err != nil {
 						log.Errorf("Setting relation foreign key value failed: %v", err)
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 						return
 					}
 					if !fields.Contains(foreignKey) {
 						fields = append(fields, foreignKey)
 					}
+					belongsTo = append(belongsTo, field)
 				}
 				payload.IncludedRelations = append(payload.IncludedRelations, &query.IncludedRelation{
 					StructField: field,
@@ -111,7 +124,7 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			err := httputil.ErrInvalidJSONFieldValue()
 			err.Detail = "Client-Generated ID is not allowed for this model."
 			err.Status = "403"
-			a.marshalErrors(rw, http.StatusForbidden, err)
+			a.marshalErrors(rw, req, http.StatusForbidden, err)
 			return
 		}
 
@@ -121,6 +134,7 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		var (
 			result          *codec.Payload
 			isTransactioner bool
+			txOpts          *query.TxOptions
 		)
 
 		// Try to get model's InsertHandler.
@@ -129,25 +143,47 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextInserter); ok {
 				if ctx, err = w.InsertWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
+					return
+				}
+			}
+			if normalizer, ok := modelHandler.(AttributeNormalizer); ok {
+				if err = normalizer.NormalizeAttributes(ctx, model, payload.FieldSets[0]); err != nil {
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
 
 			var it server.InsertTransactioner
 			if it, isTransactioner = modelHandler.(server.InsertTransactioner); isTransactioner {
-				err = database.RunInTransaction(ctx, db, it.InsertWithTransaction(), func(db database.DB) error {
-					result, err = a.insertHandleChain(ctx, db, payload)
-					return err
-				})
+				txOpts = it.InsertWithTransaction()
 			}
 		}
+		// The referenced belongs to resources are checked within the same transaction as the insert.
+		validateBelongsTo := a.Options.ValidateBelongsToExists && len(belongsTo) > 0
+		if validateBelongsTo {
+			isTransactioner = true
+		}
 
-		if !isTransactioner {
-			result, err = a.insertHandleChain(ctx, db, payload)
+		if isTransactioner {
+			err = database.RunInTransaction(ctx, db, txOpts, func(db database.DB) error {
+				if validateBelongsTo {
+					if err := a.checkBelongsToExist(ctx, db, model, belongsTo); err != nil {
+						return err
+					}
+				}
+				result, err = a.fullInsertHandlerChain(ctx, db, payload, model)
+				return err
+			})
+			if errors.Is(err, query.ErrViolation) {
+				// Deferred constraints are checked on the transaction commit.
+				err = errCommit(err)
+			}
+		} else {
+			result, err = a.fullInsertHandlerChain(ctx, db, payload, model)
 		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -160,7 +196,7 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		}
 		if len(result.Data) == 0 {
 			log.Error("No data in the result payload")
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
@@ -168,7 +204,7 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		stringID, err := model.GetPrimaryKeyStringValue()
 		if err != nil {
 			log.Errorf("Getting primary key string value failed for the model: %v", model)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
@@ -183,14 +219,47 @@ func (a *API) handleInsert(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if result.MarshalLinks.Type == codec.NoLink {
 			result.MarshalLinks = codec.LinkOptions{
 				Type:       linkType,
-				BaseURL:    a.Options.PathPrefix,
+				BaseURL:    a.linksBaseURL(),
 				RootID:     stringID,
 				Collection: mStruct.Collection(),
 			}
 		}
 		result.MarshalSingularFormat = true
-		a.marshalPayload(rw, result, http.StatusCreated)
+		a.marshalPayload(rw, req, result, http.StatusCreated)
+	}
+}
+
+// fullInsertHandlerChain executes the insert handler chain. If the RefetchAfterInsert option is set, the inserted
+// model is then refetched with all its fields and relations, so that the values set by the repository
+// (i.e. the database defaults) are present in the result.
+func (a *API) fullInsertHandlerChain(ctx context.Context, db database.DB, payload *codec.Payload, model mapping.Model) (*codec.Payload, error) {
+	result, err := a.insertHandleChain(ctx, db, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Options.RefetchAfterInsert {
+		return result, nil
+	}
+
+	// Prepare the scope for the api.GetHandler.
+	mStruct := payload.ModelStruct
+	getScope := query.NewScope(mStruct)
+	getScope.FieldSets = []mapping.FieldSet{mStruct.Fields()}
+	getScope.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
+
+	for _, relation := range mStruct.RelationFields() {
+		if err = getScope.Include(relation, relation.Relationship().RelatedModelStruct().Primary()); err != nil {
+			log.Errorf("Can't include relation field to the get scope: %v", err)
+			return nil, httputil.ErrInternalError()
+		}
+	}
+
+	getResult, err := a.getHandleChain(ctx, db, getScope)
+	if err != nil {
+		return nil, err
 	}
+	getResult.Meta = result.Meta
+	return getResult, nil
 }
 
 func (a *API) insertHandleChain(ctx context.Context, db database.DB, payload *codec.Payload) (*codec.Payload, error) {
@@ -0,0 +1,74 @@
+package jsonapi
+
+import (
+	"context"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// ParamFilterSearch is the list query parameter with the search term handled by the Searcher model handler.
+const ParamFilterSearch = "filter[search]"
+
+// NonSortableFielder is the interface implemented by the model handlers, which defines the model fields
+// that could not be sorted by (i.e. computed or virtual fields). The fields are defined by their neuron names.
+type NonSortableFielder interface {
+	NonSortableFields() []string
+}
+
+// FilterableFielder is the interface implemented by the model handlers, which defines the model fields that could
+// be filtered by in the list requests (i.e. to prevent expensive scans on the unindexed columns). The fields are
+// defined by their neuron names. The primary key is always filterable. A filter on any other field is rejected
+// with '400 Bad Request'. The models without FilterableFielder could be filtered by all their fields.
+type FilterableFielder interface {
+	FilterableFields() []string
+}
+
+// IncludeRestricter is the interface implemented by the model handlers, which restricts the relationship paths
+// that could be included in the model read endpoints responses (i.e. to prevent exposing sensitive related data).
+// The paths are dot-separated relationship neuron names, i.e. 'author.pets'. Including a path also allows
+// including each of its prefixes, i.e. 'author'. An include path not allowed is rejected with '400 Bad Request'.
+type IncludeRestricter interface {
+	AllowedIncludes() []string
+}
+
+// ScopePreparer is the interface implemented by the model handlers, which prepares the read query scopes
+// (i.e. injects filters, sorting or field restrictions) for all the read endpoints of the model: get, list,
+// get related and get relationship. The scope is prepared after the query parameters are parsed and the context
+// is set by the WithContext handlers, just before the Before* hooks are executed.
+// For the get related and relationship endpoints the scope of the related model is prepared by the related model handler.
+type ScopePreparer interface {
+	PrepareScope(ctx context.Context, s *query.Scope, method query.Method) error
+}
+
+// DefaultScoper is the interface implemented by the model handlers, which defines the default filters (i.e. not archived)
+// applied to all the read scopes of the model. The default filters are combined with the client filters using AND.
+// A default filter is not applied if the client provided the filter on the same field. The scope is provided so that
+// the handler could decide not to apply the defaults at all. The default filters are applied before the ScopePreparer.
+type DefaultScoper interface {
+	DefaultFilters(ctx context.Context, s *query.Scope) ([]filter.Filter, error)
+}
+
+// AttributeNormalizer is the interface implemented by the model handlers, which normalizes (i.e. trims or lowercases)
+// the values of the inserted or updated model. The 'fieldSet' contains the fields provided in the input.
+// It is called after the input is unmarshaled and the context is set by the WithContext handlers,
+// before the Before* hooks and the persistence.
+type AttributeNormalizer interface {
+	NormalizeAttributes(ctx context.Context, model mapping.Model, fieldSet mapping.FieldSet) error
+}
+
+// Searcher is the interface implemented by the model handlers, which handles the 'filter[search]' list query parameter.
+// It should augment the scope 's' with the filters (i.e. full-text or multi-column LIKE) for given search 'term'.
+// The list requests with the search parameter for the models without Searcher are rejected with '400 Bad Request'.
+type Searcher interface {
+	Search(ctx context.Context, s *query.Scope, term string) error
+}
+
+// UpdateResultCompleter is the interface implemented by the model handlers, which update handler results could already
+// contain the complete updated model with all its fields and relations loaded. If it returns true for the update
+// 'result' with a single model, the result is marshaled directly, without the additional query refetching the model.
+type UpdateResultCompleter interface {
+	IsUpdateResultComplete(result *codec.Payload) bool
+}
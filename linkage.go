@@ -0,0 +1,178 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// emptyLinkageRewriter creates the rewriter that sets explicit empty linkage - 'data: []', for all the
+// to-many relationships from the 'fields' that are missing the linkage in the primary data resources.
+// This allows the clients to distinguish the relationship with no members from the one that was not loaded.
+func emptyLinkageRewriter(fields mapping.FieldSet) documentRewriter {
+	var names []string
+	for _, field := range fields {
+		if field.Kind() == mapping.KindRelationshipMultiple {
+			names = append(names, field.NeuronName())
+		}
+	}
+	return func(doc document) error {
+		if len(names) == 0 {
+			return nil
+		}
+		return doc.forEachResource(func(resource object) error {
+			relationships := map[string]object{}
+			if raw, ok := resource["relationships"]; ok {
+				if err := json.Unmarshal(raw, &relationships); err != nil {
+					return err
+				}
+			}
+			for _, name := range names {
+				relationship, ok := relationships[name]
+				if !ok || relationship == nil {
+					relationship = object{}
+				}
+				if data, ok := relationship["data"]; !ok || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+					relationship["data"] = json.RawMessage("[]")
+				}
+				relationships[name] = relationship
+			}
+			raw, err := marshalJSON(relationships)
+			if err != nil {
+				return err
+			}
+			resource["relationships"] = raw
+			return nil
+		})
+	}
+}
+
+// nullLinkageRewriter creates the rewriter that sets explicit null linkage - 'data: null', for all the to-one
+// relationships from the 'fields' that are missing the linkage in the primary data resources. The linkage of the
+// requested to-one relationship is always loaded, thus the missing linkage means there is no related resource
+// (i.e. the belongs to relationship with null foreign key). If the 'fields' contains no to-one relationships
+// the rewriter is not created, so that the document is not decoded needlessly.
+func nullLinkageRewriter(fields mapping.FieldSet) (documentRewriter, bool) {
+	var names []string
+	for _, field := range fields {
+		if field.Kind() == mapping.KindRelationshipSingle {
+			names = append(names, field.NeuronName())
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	return func(doc document) error {
+		return doc.forEachResource(func(resource object) error {
+			relationships := map[string]object{}
+			if raw, ok := resource["relationships"]; ok {
+				if err := json.Unmarshal(raw, &relationships); err != nil {
+					return err
+				}
+			}
+			for _, name := range names {
+				relationship, ok := relationships[name]
+				if !ok || relationship == nil {
+					relationship = object{}
+				}
+				if _, ok := relationship["data"]; !ok {
+					relationship["data"] = json.RawMessage("null")
+				}
+				relationships[name] = relationship
+			}
+			raw, err := marshalJSON(relationships)
+			if err != nil {
+				return err
+			}
+			resource["relationships"] = raw
+			return nil
+		})
+	}, true
+}
+
+// omitEmptyRelationships is the rewriter that removes the empty 'relationships' member of the primary data
+// and included resources, i.e. when only the attributes were requested.
+func omitEmptyRelationships(doc document) error {
+	omit := func(resource object) error {
+		raw, ok := resource["relationships"]
+		if !ok {
+			return nil
+		}
+		relationships := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &relationships); err != nil {
+			return err
+		}
+		if len(relationships) == 0 {
+			delete(resource, "relationships")
+		}
+		return nil
+	}
+	if err := doc.forEachResource(omit); err != nil {
+		return err
+	}
+	raw, ok := doc["included"]
+	if !ok {
+		return nil
+	}
+	var included []object
+	if err := json.Unmarshal(raw, &included); err != nil {
+		return err
+	}
+	for _, resource := range included {
+		if err := omit(resource); err != nil {
+			return err
+		}
+	}
+	return doc.set("included", included)
+}
+
+// directRelatedLinksRewriter creates the rewriter that sets the 'related' link of the to-one relationships with
+// the known linkage to the related resource url, i.e. '/authors/1', instead of the '/books/1/author' related endpoint.
+func directRelatedLinksRewriter(prefix string) documentRewriter {
+	return func(doc document) error {
+		return doc.forEachResource(func(resource object) error {
+			raw, ok := resource["relationships"]
+			if !ok {
+				return nil
+			}
+			relationships := map[string]object{}
+			if err := json.Unmarshal(raw, &relationships); err != nil {
+				return err
+			}
+			for _, relationship := range relationships {
+				data := bytes.TrimSpace(relationship["data"])
+				if len(data) == 0 || data[0] != '{' {
+					// Only the to-one relationships with non-null linkage have the related resource url.
+					continue
+				}
+				linkage := object{}
+				if err := json.Unmarshal(data, &linkage); err != nil {
+					return err
+				}
+				related := path.Join("/", prefix, linkage.resourceType()) + "/" + escapeIDSegment(linkage.id())
+				if err := relationship.mergeMember("links", map[string]interface{}{"related": related}); err != nil {
+					return err
+				}
+			}
+			raw, err := marshalJSON(relationships)
+			if err != nil {
+				return err
+			}
+			resource["relationships"] = raw
+			return nil
+		})
+	}
+}
+
+// escapeIDSegment escapes the resource 'id' so that it is always a single path segment. The dot segments are escaped
+// too, as these are resolved by the clients as the relative path references.
+func escapeIDSegment(id string) string {
+	if id == "." || id == ".." {
+		return strings.Repeat("%2E", len(id))
+	}
+	return url.PathEscape(id)
+}
@@ -0,0 +1,240 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// testBlogFields gets the title attribute and the author belongs to relationship of the Blog model.
+func testBlogFields(t *testing.T) (title, author *mapping.StructField) {
+	t.Helper()
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs := c.MustModelStruct(&Blog{})
+	title, ok := blogs.Attribute("Title")
+	if !ok {
+		t.Fatal("no title attribute")
+	}
+	author, ok = blogs.RelationByName("Author")
+	if !ok {
+		t.Fatal("no author relationship")
+	}
+	return title, author
+}
+
+// relationshipData gets the 'data' member of the 'relationship' of the primary data 'resource'.
+func relationshipData(t *testing.T, resource object, relationship string) (json.RawMessage, bool) {
+	t.Helper()
+	relationships := map[string]object{}
+	if err := json.Unmarshal(resource["relationships"], &relationships); err != nil {
+		t.Fatalf("decoding relationships failed: %v", err)
+	}
+	data, ok := relationships[relationship]["data"]
+	return data, ok
+}
+
+func TestNullLinkageRewriter(t *testing.T) {
+	title, author := testBlogFields(t)
+
+	t.Run("NoToOne", func(t *testing.T) {
+		if _, ok := nullLinkageRewriter(mapping.FieldSet{title}); ok {
+			t.Error("rewriter created for the fieldset without to-one relationships")
+		}
+	})
+
+	name := author.NeuronName()
+	rewriter, ok := nullLinkageRewriter(mapping.FieldSet{title, author})
+	if !ok {
+		t.Fatal("no rewriter created for the fieldset with belongs to relationship")
+	}
+
+	t.Run("UnsetBelongsTo", func(t *testing.T) {
+		buf := bytes.NewBufferString(fmt.Sprintf(`{"data":{"type":"blogs","id":"1","relationships":{%q:{"links":{"related":"/blogs/1/author"}}}}}`, name))
+		if err := rewriteDocument(buf, []documentRewriter{rewriter}); err != nil {
+			t.Fatalf("rewriting document failed: %v", err)
+		}
+		data, ok := relationshipData(t, primaryResource(t, decodeDocumentBytes(t, buf.Bytes())), name)
+		if !ok || string(data) != "null" {
+			t.Errorf("expected null linkage, got: %s", data)
+		}
+	})
+
+	t.Run("SetBelongsTo", func(t *testing.T) {
+		buf := bytes.NewBufferString(fmt.Sprintf(`{"data":{"type":"blogs","id":"1","relationships":{%q:{"data":{"type":"authors","id":"2"}}}}}`, name))
+		if err := rewriteDocument(buf, []documentRewriter{rewriter}); err != nil {
+			t.Fatalf("rewriting document failed: %v", err)
+		}
+		data, _ := relationshipData(t, primaryResource(t, decodeDocumentBytes(t, buf.Bytes())), name)
+		if string(data) != `{"type":"authors","id":"2"}` {
+			t.Errorf("expected unchanged linkage, got: %s", data)
+		}
+	})
+
+	t.Run("Collection", func(t *testing.T) {
+		buf := bytes.NewBufferString(fmt.Sprintf(`{"data":[{"type":"blogs","id":"1"},{"type":"blogs","id":"2","relationships":{%q:{"data":{"type":"authors","id":"2"}}}}]}`, name))
+		if err := rewriteDocument(buf, []documentRewriter{rewriter}); err != nil {
+			t.Fatalf("rewriting document failed: %v", err)
+		}
+		var resources []object
+		if err := json.Unmarshal(decodeDocumentBytes(t, buf.Bytes())["data"], &resources); err != nil {
+			t.Fatalf("decoding primary data failed: %v", err)
+		}
+		if data, ok := relationshipData(t, resources[0], name); !ok || string(data) != "null" {
+			t.Errorf("expected null linkage, got: %s", data)
+		}
+		if data, _ := relationshipData(t, resources[1], name); string(data) == "null" {
+			t.Error("set linkage replaced with null")
+		}
+	})
+}
+
+func decodeDocumentBytes(t *testing.T, data []byte) document {
+	t.Helper()
+	doc := document{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decoding document failed: %v", err)
+	}
+	return doc
+}
+
+func TestEmptyLinkageRewriter(t *testing.T) {
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs, ok := c.MustModelStruct(&Author{}).RelationByName("Blogs")
+	if !ok {
+		t.Fatal("no blogs relationship")
+	}
+	name := blogs.NeuronName()
+	rewriter := emptyLinkageRewriter(mapping.FieldSet{blogs})
+
+	buf := bytes.NewBufferString(fmt.Sprintf(`{"data":[{"type":"authors","id":"1"},{"type":"authors","id":"2","relationships":{%q:{"data":null}}},{"type":"authors","id":"3","relationships":{%q:{"data":[{"type":"blogs","id":"1"}]}}}]}`, name, name))
+	if err := rewriteDocument(buf, []documentRewriter{rewriter}); err != nil {
+		t.Fatalf("rewriting document failed: %v", err)
+	}
+	var resources []object
+	if err := json.Unmarshal(decodeDocumentBytes(t, buf.Bytes())["data"], &resources); err != nil {
+		t.Fatalf("decoding primary data failed: %v", err)
+	}
+	for i, expected := range []string{`[]`, `[]`, `[{"type":"blogs","id":"1"}]`} {
+		if data, _ := relationshipData(t, resources[i], name); string(data) != expected {
+			t.Errorf("resource: %d expected linkage: %s, got: %s", i, expected, data)
+		}
+	}
+}
+
+func TestExplicitEmptyLinkage(t *testing.T) {
+	ta := newTestAPI(t, nil, WithExplicitEmptyLinkage())
+	ta.repo.store(ta.authors, &Author{ID: 2, Name: "empty"})
+	ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 3})
+	blogs, ok := ta.authors.RelationByName("Blogs")
+	if !ok {
+		t.Fatal("no blogs relationship")
+	}
+	name := blogs.NeuronName()
+
+	linkages := func(t *testing.T, target string) map[string]string {
+		t.Helper()
+		rec := ta.serve(http.MethodGet, target, "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+		data := decodeDocument(t, rec)["data"]
+		var resources []object
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+			if err := json.Unmarshal(data, &resources); err != nil {
+				t.Fatalf("decoding primary data failed: %v", err)
+			}
+		} else {
+			resource := object{}
+			if err := json.Unmarshal(data, &resource); err != nil {
+				t.Fatalf("decoding primary data failed: %v", err)
+			}
+			resources = append(resources, resource)
+		}
+		result := map[string]string{}
+		for _, resource := range resources {
+			var id string
+			if err := json.Unmarshal(resource["id"], &id); err != nil {
+				t.Fatalf("decoding resource id failed: %v", err)
+			}
+			data, ok := relationshipData(t, resource, name)
+			if !ok {
+				t.Fatalf("resource: '%s' has no '%s' linkage", id, name)
+			}
+			result[id] = string(data)
+		}
+		return result
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		if data := linkages(t, "/authors/2")["2"]; data != "[]" {
+			t.Errorf("expected empty linkage, got: %s", data)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		result := linkages(t, "/authors")
+		if result["2"] != "[]" {
+			t.Errorf("expected empty linkage, got: %s", result["2"])
+		}
+		if result["3"] != `[{"type":"blogs","id":"1"}]` {
+			t.Errorf("expected unchanged linkage, got: %s", result["3"])
+		}
+	})
+
+	t.Run("GetRelated", func(t *testing.T) {
+		if data := linkages(t, "/blogs/1/author")["3"]; data != `[{"type":"blogs","id":"1"}]` {
+			t.Errorf("expected unchanged linkage, got: %s", data)
+		}
+	})
+
+	t.Run("SparseFieldset", func(t *testing.T) {
+		// The relationship not requested by the fieldset is not marshaled.
+		rec := ta.serve(http.MethodGet, "/authors/2?fields[authors]=name", "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+		resource := primaryResource(t, decodeDocument(t, rec))
+		if _, ok := resource["relationships"]; !ok {
+			return
+		}
+		if _, ok := relationshipData(t, resource, name); ok {
+			t.Error("not requested relationship marshaled with the empty linkage")
+		}
+	})
+}
+
+func TestDirectRelatedLinksRewriter(t *testing.T) {
+	for id, expected := range map[string]string{
+		"2":     "/api/authors/2",
+		"a/b":   "/api/authors/a%2Fb",
+		"..":    "/api/authors/%2E%2E",
+		"a b?c": "/api/authors/a%20b%3Fc",
+	} {
+		linkage, err := json.Marshal(map[string]string{"type": "authors", "id": id})
+		if err != nil {
+			t.Fatalf("marshaling linkage failed: %v", err)
+		}
+		buf := bytes.NewBufferString(fmt.Sprintf(`{"data":{"type":"blogs","id":"1","relationships":{"author":{"data":%s}}}}`, linkage))
+		if err := rewriteDocument(buf, []documentRewriter{directRelatedLinksRewriter("/api")}); err != nil {
+			t.Fatalf("rewriting document failed: %v", err)
+		}
+		relationships := map[string]struct {
+			Links map[string]string `json:"links"`
+		}{}
+		if err := json.Unmarshal(primaryResource(t, decodeDocumentBytes(t, buf.Bytes()))["relationships"], &relationships); err != nil {
+			t.Fatalf("decoding relationships failed: %v", err)
+		}
+		if related := relationships["author"].Links["related"]; related != expected {
+			t.Errorf("id: '%s' expected related link: '%s', got: '%s'", id, expected, related)
+		}
+	}
+}
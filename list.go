@@ -2,6 +2,7 @@ package jsonapi
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/neuronlabs/neuron/server"
 
 	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 )
 
@@ -25,9 +27,13 @@ func (a *API) HandleList(model mapping.Model) http.HandlerFunc {
 
 func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 	var defaultPagination *query.Pagination
-	if a.Options.DefaultPageSize > 0 {
+	pageSize := a.Options.DefaultPageSize
+	if modelPageSize, ok := a.pageSizes[mStruct]; ok {
+		pageSize = modelPageSize
+	}
+	if pageSize > 0 {
 		defaultPagination = &query.Pagination{
-			Limit:  int64(a.Options.DefaultPageSize),
+			Limit:  int64(pageSize),
 			Offset: 0,
 		}
 		log.Debug2f("Default pagination at 'GET /%s' is: %v", mStruct.Collection(), defaultPagination.String())
@@ -36,11 +42,29 @@ func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		s, err := a.createListScope(mStruct, req)
 		if err != nil {
 			log.Debugf("[LIST][%s] parsing request query failed: %v", mStruct, err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
-		if defaultPagination != nil && s.Pagination == nil {
+		if err = a.checkSortableFields(s); err != nil {
+			log.Debugf("[LIST][%s] %v", mStruct, err)
+			a.marshalErrors(rw, req, 400, err)
+			return
+		}
+
+		// The NDJSON streamed lists are not paginated by default - these are limited by the NDJSONMaxResults.
+		ndjson := a.Options.AllowNDJSON && acceptsNDJSON(req)
+		if ndjson && s.Pagination == nil && a.Options.NDJSONMaxResults > 0 {
+			s.Pagination = &query.Pagination{Limit: int64(a.Options.NDJSONMaxResults)}
+		}
+
+		// The 'Range' header pagination is used only if no pagination query parameters were provided.
+		var rangePagination bool
+		if !ndjson && a.Options.AllowRangePagination && s.Pagination == nil {
+			s.Pagination, rangePagination = parseRangePagination(req.Header.Get("Range"))
+		}
+
+		if !ndjson && defaultPagination != nil && s.Pagination == nil {
 			s.Pagination = &(*defaultPagination)
 		}
 
@@ -48,21 +72,45 @@ func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		queryIncludes := s.IncludedRelations
 		var queryFieldSet mapping.FieldSet
 		var fields mapping.FieldSet
-		if len(s.FieldSets) == 0 {
+		if defaultFields, ok := a.listFields[mStruct]; ok && len(s.FieldSets) == 0 {
+			fields = append(mapping.FieldSet{}, defaultFields...)
+			queryFieldSet = fields
+		} else if len(s.FieldSets) == 0 {
 			fields = append(s.ModelStruct.Attributes(), s.ModelStruct.RelationFields()...)
 			queryFieldSet = fields
 		} else {
 			fields = s.FieldSets[0]
 			queryFieldSet = s.FieldSets[0]
 		}
+		// The ids-only profile lists only the resource identifiers - the attributes and relationships are not loaded.
+		if CtxHasProfile(req.Context(), ProfileIDsOnly) {
+			queryIncludes = nil
+			fields = mapping.FieldSet{}
+			queryFieldSet = fields
+		}
 		// json:api fieldset is a combination of fields + relations.
 		// The same situation is with includes.
 		neuronFields, neuronIncludes := parseFieldSetAndIncludes(mStruct, fields, queryIncludes)
+		if a.Options.EnableETag {
+			// The collection ETag is computed using the 'updated at' timestamps.
+			if updatedAt, ok := mStruct.UpdatedAt(); ok && !neuronFields.Contains(updatedAt) {
+				neuronFields = append(neuronFields, updatedAt)
+			}
+		}
+		// The locale is needed for the 'Content-Language' header.
+		localeField, hasLocale := a.localeField(mStruct)
+		if hasLocale && !neuronFields.Contains(localeField) {
+			neuronFields = append(neuronFields, localeField)
+		}
 		s.FieldSets = []mapping.FieldSet{neuronFields}
+		var foreignKeyRelations []*mapping.StructField
+		if a.Options.ForeignKeyLinkage {
+			neuronIncludes, foreignKeyRelations = foreignKeyLinkageRelations(neuronIncludes, queryIncludes)
+		}
 		s.IncludedRelations = neuronIncludes
 
 		ctx := req.Context()
-		db := a.DB
+		db := a.readDB()
 		var (
 			result          *codec.Payload
 			isTransactioner bool
@@ -72,10 +120,15 @@ func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			if w, ok := modelHandler.(server.WithContextLister); ok {
 				ctx, err = w.ListWithContext(ctx)
 				if err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
+			// The default filters and the scope preparer are applied only to the read endpoint scopes.
+			if err = a.prepareScope(ctx, s, query.List); err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
 
 			var t server.ListTransactioner
 			if t, isTransactioner = modelHandler.(server.ListTransactioner); isTransactioner {
@@ -90,10 +143,31 @@ func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			result, err = a.listHandleChain(ctx, db, s)
 		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
+		if hasLocale {
+			if err = setContentLanguage(rw, localeField, result.Data); err != nil {
+				log.Errorf("[LIST][%s] getting resource locale failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+		}
+
+		if ndjson {
+			a.writeNDJSON(rw, mStruct, queryFieldSet, result.Data)
+			return
+		}
+
+		if len(foreignKeyRelations) > 0 {
+			if err = setForeignKeyLinkage(result.Data, foreignKeyRelations); err != nil {
+				log.Errorf("[LIST][%s] setting foreign key linkage failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+		}
+
 		linkType := codec.ResourceLink
 		if !a.Options.PayloadLinks {
 			linkType = codec.NoLink
@@ -114,121 +188,172 @@ func (a *API) handleList(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if result.MarshalLinks.Type == codec.NoLink {
 			result.MarshalLinks = codec.LinkOptions{
 				Type:       linkType,
-				BaseURL:    a.Options.PathPrefix,
+				BaseURL:    a.linksBaseURL(),
 				Collection: mStruct.Collection(),
 			}
 		}
 
+		var rewriters []documentRewriter
+		if provider, ok := modelHandler.(ResourceMetaProvider); ok {
+			rewriter, err := resourceMetaRewriter(ctx, provider, result.Data)
+			if err != nil {
+				log.Debugf("[LIST][%s] getting resource meta failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			rewriters = append(rewriters, rewriter)
+		}
+		if rewriter, ok := nullLinkageRewriter(queryFieldSet); ok {
+			rewriters = append(rewriters, rewriter)
+		}
+		if a.Options.ExplicitEmptyLinkage {
+			rewriters = append(rewriters, emptyLinkageRewriter(queryFieldSet))
+		}
+		if a.Options.DirectRelatedLinks {
+			rewriters = append(rewriters, directRelatedLinksRewriter(a.Options.PathPrefix))
+		}
+
+		if a.Options.EchoQueryMeta {
+			meta := queryMeta(s)
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("meta", map[string]interface{}{"query": meta})
+			})
+		}
+
+		if a.Options.FullPaginationLinks {
+			rewriters = append(rewriters, fullPaginationLinks)
+		}
+
 		// if there is no pagination then the pagination doesn't need to be created.
 		// marshal the results if there were no pagination set
-		if s.Pagination == nil || len(s.Models) == 0 {
+		if s.Pagination == nil {
 			result.PaginationLinks = &codec.PaginationLinks{}
 			sb := strings.Builder{}
-			sb.WriteString(a.basePath())
-			sb.WriteRune('/')
-			sb.WriteString(mStruct.Collection())
+			sb.WriteString(a.listPath(req, mStruct))
 			if q := req.URL.Query(); len(q) > 0 {
 				sb.WriteRune('?')
 				sb.WriteString(q.Encode())
 			}
 			result.PaginationLinks.Self = sb.String()
-			a.marshalPayload(rw, result, http.StatusOK)
+			if a.Options.EnableETag {
+				etag, err := collectionETag(a.Options.ETagSalt, mStruct, result.Data, req.URL.Query().Encode())
+				if err != nil {
+					log.Errorf("[LIST][%s] computing collection ETag failed: %v", mStruct, err)
+					a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+					return
+				}
+				if notModified(rw, req, etag) {
+					return
+				}
+			}
+			a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 			return
 		}
 
 		// prepare new count scope - and build query parameters for the pagination.
 		// page[limit] page[offset] page[number] page[size]
-		countScope := s.Copy()
-		total, err := database.Count(req.Context(), a.DB, countScope)
-		if err != nil {
-			log.Debugf("[LIST][%s] Getting total values for given query failed: %v", mStruct, err)
-			a.marshalErrors(rw, 0, err)
-			return
+		// The empty first page means that no resources matches the query - the total is known to be zero.
+		// The result data is checked, as the custom list handlers don't need to set the scope models.
+		var total int64
+		if len(result.Data) != 0 || s.Pagination.Offset != 0 {
+			countScope := s.Copy()
+			total, err = database.Count(req.Context(), db, countScope)
+			if err != nil {
+				log.Debugf("[LIST][%s] Getting total values for given query failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
 		}
-
-		temp, pageBased := a.queryWithoutPagination(req)
-
-		// extract query values from the req.URL
-		// prepare the pagination links for the options
-		jsonapi.FormatPagination(s.Pagination, temp, pageBased)
-
-		paginationLinks := &codec.PaginationLinks{Total: total}
-		sb := strings.Builder{}
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
-		sb.WriteRune('?')
-		sb.WriteString(temp.Encode())
-		paginationLinks.Self = sb.String()
-		sb.Reset()
-
-		next, err := s.Pagination.Next(total)
-		if err != nil {
-			a.marshalErrors(rw, 0, err)
+		if a.Options.PaginationOutOfRange == OutOfRangeError && s.Pagination.Offset > 0 && s.Pagination.Offset >= total {
+			log.Debugf("[LIST][%s] pagination offset: %d exceeds the total: %d", mStruct, s.Pagination.Offset, total)
+			a.marshalErrors(rw, req, 400, errInvalidParameter("page", "requested page is out of range"))
 			return
 		}
-		temp, _ = a.queryWithoutPagination(req)
-
-		if next != s.Pagination {
-			jsonapi.FormatPagination(next, temp, pageBased)
-			sb.WriteString(a.basePath())
-			sb.WriteRune('/')
-			sb.WriteString(mStruct.Collection())
-			sb.WriteRune('?')
-			sb.WriteString(temp.Encode())
-			paginationLinks.Next = sb.String()
-			sb.Reset()
-			temp, _ = a.queryWithoutPagination(req)
+		// The default filters are always set for the scoped models, thus only the client filters are checked.
+		if a.Options.IncludeUnfilteredTotal && hasFilterParameters(req.URL.Query()) {
+			unfilteredTotal, err := a.unfilteredTotal(ctx, req, db, mStruct)
+			if err != nil {
+				log.Debugf("[LIST][%s] Getting unfiltered total values failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("meta", map[string]interface{}{"unfilteredTotal": unfilteredTotal})
+			})
+		}
+		if a.Options.EnableETag {
+			etag, err := collectionETag(a.Options.ETagSalt, mStruct, result.Data, req.URL.Query().Encode(), total)
+			if err != nil {
+				log.Errorf("[LIST][%s] computing collection ETag failed: %v", mStruct, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+			if notModified(rw, req, etag) {
+				return
+			}
 		}
 
-		prev, err := s.Pagination.Previous()
+		paginationLinks, err := a.paginationLinks(req, a.listPath(req, mStruct), s.Pagination, total)
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
-		if prev != s.Pagination {
-			jsonapi.FormatPagination(prev, temp, pageBased)
-			sb.WriteString(a.basePath())
-			sb.WriteRune('/')
-			sb.WriteString(mStruct.Collection())
-			sb.WriteRune('?')
-			sb.WriteString(temp.Encode())
-			paginationLinks.Prev = sb.String()
-			sb.Reset()
-			temp, _ = a.queryWithoutPagination(req)
+		result.PaginationLinks = paginationLinks
+		if rangePagination {
+			writeContentRange(rw, s.Pagination, len(result.Data), total)
+			a.marshalPayload(rw, req, result, http.StatusPartialContent, rewriters...)
+			return
 		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
+	}
+}
 
-		last, err := s.Pagination.Last(total)
-		if err != nil {
-			a.marshalErrors(rw, 0, err)
-			return
+// checkSortableFields checks if none of the scope sorting fields is listed as non-sortable
+// by the model's NonSortableFielder handler.
+func (a *API) checkSortableFields(s *query.Scope) error {
+	if len(s.SortingOrder) == 0 {
+		return nil
+	}
+	nonSortable, ok := a.handlers[s.ModelStruct].(NonSortableFielder)
+	if !ok {
+		return nil
+	}
+	fields := map[string]struct{}{}
+	for _, name := range nonSortable.NonSortableFields() {
+		fields[name] = struct{}{}
+	}
+	for _, sort := range s.SortingOrder {
+		field := sort.Field()
+		if _, ok := fields[field.NeuronName()]; ok {
+			return errInvalidParameter("sort", fmt.Sprintf("Sorting by the field: '%s' is not supported.", field.NeuronName()))
 		}
-		jsonapi.FormatPagination(last, temp, pageBased)
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
-		sb.WriteRune('?')
-		sb.WriteString(temp.Encode())
-		paginationLinks.Last = sb.String()
-		sb.Reset()
+	}
+	return nil
+}
 
-		temp, _ = a.queryWithoutPagination(req)
-		first, err := s.Pagination.First()
-		if err != nil {
-			a.marshalErrors(rw, 0, err)
-			return
+// unfilteredTotal counts the 'mStruct' collection resources without the client filters. The header scope enricher,
+// default filters and the scope preparer are applied, so that only the resources visible to the client are counted.
+func (a *API) unfilteredTotal(ctx context.Context, req *http.Request, db database.DB, mStruct *mapping.ModelStruct) (int64, error) {
+	s := query.NewScope(mStruct)
+	if a.Options.HeaderScopeEnricher != nil {
+		if err := a.Options.HeaderScopeEnricher(req, s); err != nil {
+			return 0, err
 		}
-		jsonapi.FormatPagination(first, temp, pageBased)
-		sb.WriteString(a.basePath())
-		sb.WriteRune('/')
-		sb.WriteString(mStruct.Collection())
-		sb.WriteRune('?')
-		sb.WriteString(temp.Encode())
-		paginationLinks.First = sb.String()
+	}
+	if err := a.prepareScope(ctx, s, query.List); err != nil {
+		return 0, err
+	}
+	return database.Count(ctx, db, s)
+}
 
-		result.PaginationLinks = paginationLinks
-		a.marshalPayload(rw, result, http.StatusOK)
+// hasFilterParameters checks if the query 'values' contains any client filter parameter.
+func hasFilterParameters(values url.Values) bool {
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			return true
+		}
 	}
+	return false
 }
 
 func (a *API) queryWithoutPagination(req *http.Request) (url.Values, bool) {
@@ -0,0 +1,218 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// copyingLister is the list handler that finds the models with the copy of the query scope,
+// so that the scope models are not set.
+type copyingLister struct{}
+
+// HandleList implements server.ListHandler interface.
+func (copyingLister) HandleList(ctx context.Context, db database.DB, q *query.Scope) (*codec.Payload, error) {
+	models, err := db.(database.QueryFinder).QueryFind(ctx, q.Copy())
+	if err != nil {
+		return nil, err
+	}
+	return &codec.Payload{Data: models}, nil
+}
+
+// listPage is the decoded list response page.
+type listPage struct {
+	ids   []string
+	total int64
+	links map[string]string
+}
+
+// serveListPage sends the list request to the 'target' and decodes the response page.
+func serveListPage(t *testing.T, ta *testAPI, target string) listPage {
+	t.Helper()
+	rec := ta.serve(http.MethodGet, target, "", "Accept", jsonapi.MimeType)
+	expectStatus(t, rec, http.StatusOK)
+
+	var (
+		doc       = decodeDocument(t, rec)
+		resources []object
+		page      = listPage{links: map[string]string{}}
+		meta      struct {
+			Total int64 `json:"total"`
+		}
+	)
+	if err := json.Unmarshal(doc["data"], &resources); err != nil {
+		t.Fatalf("decoding primary data failed: %v", err)
+	}
+	for _, resource := range resources {
+		page.ids = append(page.ids, resource.id())
+	}
+	if err := json.Unmarshal(doc["meta"], &meta); err != nil {
+		t.Fatalf("decoding meta failed: %v", err)
+	}
+	page.total = meta.Total
+	if err := json.Unmarshal(doc["links"], &page.links); err != nil {
+		t.Fatalf("decoding links failed: %v", err)
+	}
+	return page
+}
+
+// pageOffset gets the 'page[offset]' of the pagination 'link'.
+func pageOffset(t *testing.T, link string) int64 {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parsing link: '%s' failed: %v", link, err)
+	}
+	offset := u.Query().Get(query.ParamPageOffset)
+	if offset == "" {
+		return 0
+	}
+	value, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil {
+		t.Fatalf("parsing link: '%s' offset failed: %v", link, err)
+	}
+	return value
+}
+
+func TestHandleListPagination(t *testing.T) {
+	newListAPI := func(t *testing.T, options ...Option) *testAPI {
+		ta := newTestAPI(t, nil, options...)
+		for i := 1; i <= 5; i++ {
+			ta.storeBlogs(&Blog{ID: i, Title: "title"})
+		}
+		return ta
+	}
+
+	t.Run("EmptyFirstPage", func(t *testing.T) {
+		ta := newListAPI(t)
+		page := serveListPage(t, ta, "/blogs?page[limit]=2&page[offset]=0&filter[blogs][title]=none")
+		if len(page.ids) != 0 || page.total != 0 {
+			t.Errorf("expected empty page with zero total, got: %v, total: %d", page.ids, page.total)
+		}
+		if offset := pageOffset(t, page.links["last"]); offset != 0 {
+			t.Errorf("expected the last page at offset: 0, got: %d", offset)
+		}
+		if next, ok := page.links["next"]; ok {
+			t.Errorf("unexpected next link of the empty page: %s", next)
+		}
+	})
+
+	t.Run("ShortLastPage", func(t *testing.T) {
+		ta := newListAPI(t)
+		page := serveListPage(t, ta, "/blogs?page[limit]=2&page[offset]=4")
+		if len(page.ids) != 1 || page.total != 5 {
+			t.Errorf("expected single resource with total: 5, got: %v, total: %d", page.ids, page.total)
+		}
+		if offset := pageOffset(t, page.links["last"]); offset != 4 {
+			t.Errorf("expected the last page at offset: 4, got: %d", offset)
+		}
+		if next, ok := page.links["next"]; ok {
+			t.Errorf("unexpected next link of the last page: %s", next)
+		}
+	})
+
+	t.Run("PastTheEnd", func(t *testing.T) {
+		ta := newListAPI(t)
+		page := serveListPage(t, ta, "/blogs?page[limit]=2&page[offset]=10")
+		if len(page.ids) != 0 || page.total != 5 {
+			t.Errorf("expected empty page with total: 5, got: %v, total: %d", page.ids, page.total)
+		}
+		if offset := pageOffset(t, page.links["last"]); offset != 4 {
+			t.Errorf("expected the last page at offset: 4, got: %d", offset)
+		}
+	})
+
+	t.Run("CustomListHandler", func(t *testing.T) {
+		ta := newListAPI(t, WithModelHandler(&Blog{}, copyingLister{}))
+		page := serveListPage(t, ta, "/blogs?page[limit]=2&page[offset]=0")
+		if len(page.ids) != 2 || page.total != 5 {
+			t.Errorf("expected two resources with total: 5, got: %v, total: %d", page.ids, page.total)
+		}
+		if offset := pageOffset(t, page.links["last"]); offset != 4 {
+			t.Errorf("expected the last page at offset: 4, got: %d", offset)
+		}
+	})
+}
+
+func TestHandleListETagFieldSets(t *testing.T) {
+	ta := newTestAPI(t, nil, WithETag())
+	ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body"})
+
+	etag := func(target string, headers ...string) string {
+		t.Helper()
+		rec := ta.serve(http.MethodGet, target, "", append([]string{"Accept", jsonapi.MimeType}, headers...)...)
+		expectStatus(t, rec, http.StatusOK)
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: no ETag in the list response", target)
+		}
+		return etag
+	}
+	titleETag := etag("/blogs?fields[blogs]=title")
+	bodyETag := etag("/blogs?fields[blogs]=body")
+	if titleETag == bodyETag {
+		t.Errorf("different fieldsets have the same ETag: %s", titleETag)
+	}
+	if again := etag("/blogs?fields[blogs]=title"); again != titleETag {
+		t.Errorf("the same fieldset has different ETags: %s, %s", titleETag, again)
+	}
+	// The cached response of the other fieldset is not valid for the request.
+	etag("/blogs?fields[blogs]=body", "If-None-Match", titleETag)
+}
+
+func TestHandleListUnfilteredTotal(t *testing.T) {
+	unfilteredTotal := func(t *testing.T, ta *testAPI, target string) (interface{}, bool) {
+		t.Helper()
+		rec := ta.serve(http.MethodGet, target, "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+		meta := map[string]interface{}{}
+		if raw, ok := decodeDocument(t, rec)["meta"]; ok {
+			if err := json.Unmarshal(raw, &meta); err != nil {
+				t.Fatalf("decoding meta failed: %v", err)
+			}
+		}
+		total, ok := meta["unfilteredTotal"]
+		return total, ok
+	}
+
+	t.Run("Filtered", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithIncludeUnfilteredTotal())
+		ta.storeBlogs(&Blog{ID: 1, Title: "a"}, &Blog{ID: 2, Title: "a"}, &Blog{ID: 3, Title: "b"})
+
+		if total, ok := unfilteredTotal(t, ta, "/blogs?filter[title]=a&page[limit]=10"); !ok || total != float64(3) {
+			t.Errorf("expected unfiltered total: 3, got: %v", total)
+		}
+	})
+
+	t.Run("NoFilters", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithIncludeUnfilteredTotal())
+		ta.storeBlogs(&Blog{ID: 1, Title: "a"})
+
+		if total, ok := unfilteredTotal(t, ta, "/blogs?page[limit]=10"); ok {
+			t.Errorf("unfiltered total set for the query without filters: %v", total)
+		}
+	})
+
+	t.Run("DefaultScope", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithIncludeUnfilteredTotal(), WithModelHandler(&Blog{}, &visibleBlogs{}))
+		ta.storeBlogs(&Blog{ID: 1, Title: "visible"}, &Blog{ID: 2, Title: "visible"}, &Blog{ID: 3, Title: "hidden"})
+
+		// The default filters are not the client filters - the unfiltered total is not set.
+		if total, ok := unfilteredTotal(t, ta, "/blogs?page[limit]=10"); ok {
+			t.Errorf("unfiltered total set for the query without client filters: %v", total)
+		}
+		// The resources out of the default scope are not counted.
+		if total, ok := unfilteredTotal(t, ta, "/blogs?filter[id]=1&page[limit]=10"); !ok || total != float64(2) {
+			t.Errorf("expected unfiltered total: 2, got: %v", total)
+		}
+	})
+}
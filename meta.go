@@ -0,0 +1,35 @@
+package jsonapi
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/codec"
+)
+
+// marshalMeta writes the meta-only json:api document with given 'meta' and optional 'links' members.
+// It is used by the endpoints which responses are not model resources (i.e. counts, actions or aggregations).
+// A top-level json:api document requires at least one of the 'data', 'errors' or 'meta' members,
+// thus the 'meta' member is always present.
+func (a *API) marshalMeta(rw http.ResponseWriter, req *http.Request, meta codec.Meta, links map[string]string, status int) {
+	if meta == nil {
+		meta = codec.Meta{}
+	}
+	doc := map[string]interface{}{"meta": meta}
+	if len(links) > 0 {
+		doc["links"] = links
+	}
+	buf := &bytes.Buffer{}
+	if err := encodeJSON(buf, doc); err != nil {
+		log.Errorf("Marshaling meta document failed: %v", err)
+		a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+		return
+	}
+	a.writeContentType(rw, CtxProfiles(req.Context())...)
+	rw.WriteHeader(status)
+	if _, err := rw.Write(buf.Bytes()); err != nil {
+		log.Errorf("Writing to response writer failed: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/server"
+)
+
+// HeaderMethodOverride is the header of the POST requests which overrides the request method,
+// for the clients behind the proxies blocking the PATCH and DELETE methods.
+const HeaderMethodOverride = "X-HTTP-Method-Override"
+
+// defaultOverrideMethods are the methods allowed in the HeaderMethodOverride by default.
+var defaultOverrideMethods = []string{http.MethodPatch, http.MethodDelete}
+
+// MidMethodOverride creates a middleware that rewrites the method of the POST requests with the HeaderMethodOverride
+// header. The override must be one of the 'allowed' methods, by default PATCH or DELETE, otherwise the request is
+// rejected with the '400 Bad Request' status. The middleware needs to wrap the router, so that it runs before
+// the route dispatch.
+func MidMethodOverride(allowed ...string) server.Middleware {
+	if len(allowed) == 0 {
+		allowed = defaultOverrideMethods
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			method, err := overrideMethod(req, allowed)
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				c, ok := controller.CtxGet(req.Context())
+				if !ok {
+					return
+				}
+				jsonapi.GetCodec(c).MarshalErrors(rw, err)
+				return
+			}
+			if method != "" {
+				req.Method = method
+			}
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// overrideMethod gets the method of the POST request HeaderMethodOverride header. If the request is not overridden
+// an empty method is returned. If the override is not one of the 'allowed' methods, the bad request error is returned.
+func overrideMethod(req *http.Request, allowed []string) (string, *codec.Error) {
+	if req.Method != http.MethodPost {
+		return "", nil
+	}
+	method := strings.ToUpper(strings.TrimSpace(req.Header.Get(HeaderMethodOverride)))
+	if method == "" {
+		return "", nil
+	}
+	if !isAllowedValue(method, allowed) {
+		err := httputil.ErrBadRequest()
+		err.Detail = fmt.Sprintf("header: '%s' value: '%s' is not one of: '%s'", HeaderMethodOverride, method, strings.Join(allowed, "', '"))
+		return "", err
+	}
+	return method, nil
+}
+
+// overridable wraps the POST route 'handle', so that the requests with the HeaderMethodOverride header are dispatched
+// to the 'router' route of the overriding method, if the AllowMethodOverride option is set.
+func (a *API) overridable(router *httprouter.Router, handle httprouter.Handle) httprouter.Handle {
+	if !a.Options.AllowMethodOverride {
+		return handle
+	}
+	return func(rw http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if !a.dispatchOverride(router, rw, req) {
+			handle(rw, req, params)
+		}
+	}
+}
+
+// methodOverrideFallback wraps the 'router' method not allowed handler, so that the POST requests with
+// the HeaderMethodOverride header are dispatched on the paths without the POST routes, i.e. '/articles/:id'.
+func (a *API) methodOverrideFallback(router *httprouter.Router, fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if a.dispatchOverride(router, rw, req) {
+			return
+		}
+		if fallback != nil {
+			fallback.ServeHTTP(rw, req)
+			return
+		}
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	})
+}
+
+// dispatchOverride dispatches the request with the HeaderMethodOverride header to the 'router' route of the
+// overriding method. It returns false if the request is not overridden.
+func (a *API) dispatchOverride(router *httprouter.Router, rw http.ResponseWriter, req *http.Request) bool {
+	method, err := overrideMethod(req, defaultOverrideMethods)
+	if err != nil {
+		log.Debugf("[%s] %s invalid method override: %v", req.Method, req.URL.Path, err)
+		a.marshalErrors(rw, req, 0, err)
+		return true
+	}
+	if method == "" {
+		return false
+	}
+	handle, params, _ := router.Lookup(method, req.URL.Path)
+	if handle == nil {
+		log.Debugf("[%s] %s no route for the method override: '%s'", req.Method, req.URL.Path, method)
+		a.marshalErrors(rw, req, http.StatusMethodNotAllowed, newError(http.StatusMethodNotAllowed, fmt.Sprintf("method: '%s' is not allowed for the resource", method)))
+		return true
+	}
+	req.Method = method
+	handle(rw, req, params)
+	return true
+}
@@ -2,19 +2,35 @@ package jsonapi
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
 	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
 	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/server"
 )
 
 // MidAccept creates a middleware that requires provided accept
 func MidAccept(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		parsed := httputil.ParseAcceptHeader(req.Header)
-		for _, qv := range parsed {
-			if qv.Value == jsonapi.MimeType {
+		// json:api documents are always encoded in UTF-8.
+		if !acceptsUTF8(req.Header.Get("Accept-Charset")) {
+			rw.WriteHeader(http.StatusNotAcceptable)
+			c, ok := controller.CtxGet(req.Context())
+			if !ok {
+				return
+			}
+			err := httputil.ErrUnsupportedHeader()
+			err.Detail = "header Accept-Charset doesn't allow 'utf-8' charset"
+			jsonapi.GetCodec(c).MarshalErrors(rw, err)
+			return
+		}
+		for _, mediaType := range parseMediaTypes(req.Header.Get("Accept")) {
+			if mediaType.isJSONAPI() {
 				next.ServeHTTP(rw, req)
 				return
 			}
@@ -34,11 +50,151 @@ func MidAccept(next http.Handler) http.Handler {
 // MidAccept creates a middleware that requires provided accept
 func MidContentType(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		ct := req.Header.Get("Content-Type")
-		if ct == jsonapi.MimeType {
+		mediaTypes := parseMediaTypes(req.Header.Get("Content-Type"))
+		if len(mediaTypes) == 1 && mediaTypes[0].isJSONAPI() {
 			next.ServeHTTP(rw, req)
 			return
 		}
 		rw.WriteHeader(http.StatusUnsupportedMediaType)
 	})
 }
+
+// MidRequireHeader creates a middleware that requires the request header 'name' to be present.
+// If any 'allowed' values are provided, the header value must be one of them. Otherwise the request is
+// rejected with the '400 Bad Request' status.
+func MidRequireHeader(name string, allowed ...string) server.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			value := strings.TrimSpace(req.Header.Get(name))
+			var detail string
+			switch {
+			case value == "":
+				detail = fmt.Sprintf("missing required header: '%s'", name)
+			case len(allowed) > 0 && !isAllowedValue(value, allowed):
+				detail = fmt.Sprintf("header: '%s' value: '%s' is not one of: '%s'", name, value, strings.Join(allowed, "', '"))
+			default:
+				next.ServeHTTP(rw, req)
+				return
+			}
+			rw.WriteHeader(http.StatusBadRequest)
+			c, ok := controller.CtxGet(req.Context())
+			if !ok {
+				return
+			}
+			err := httputil.ErrBadRequest()
+			err.Detail = detail
+			jsonapi.GetCodec(c).MarshalErrors(rw, err)
+		})
+	}
+}
+
+func isAllowedValue(value string, allowed []string) bool {
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MidRequireTLS creates a middleware that rejects the requests not made over TLS with the '403 Forbidden' status.
+// If 'trustForwardedProto' is set, the 'X-Forwarded-Proto' header set by the trusted proxy is also checked.
+// The successful responses contain the 'Strict-Transport-Security' header with given 'maxAge', if it is positive.
+func MidRequireTLS(trustForwardedProto bool, maxAge time.Duration) server.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			isTLS := req.TLS != nil
+			if !isTLS && trustForwardedProto {
+				isTLS = strings.EqualFold(strings.TrimSpace(req.Header.Get("X-Forwarded-Proto")), "https")
+			}
+			if !isTLS {
+				rw.WriteHeader(http.StatusForbidden)
+				c, ok := controller.CtxGet(req.Context())
+				if !ok {
+					return
+				}
+				err := newError(http.StatusForbidden, "the API requires a secure connection (https)")
+				jsonapi.GetCodec(c).MarshalErrors(rw, err)
+				return
+			}
+			if maxAge > 0 {
+				rw.Header().Set("Strict-Transport-Security", "max-age="+strconv.FormatInt(int64(maxAge/time.Second), 10))
+			}
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// mediaType is a single media type parsed from the 'Accept' or 'Content-Type' header.
+type mediaType struct {
+	value  string
+	params map[string]string
+}
+
+// isJSONAPI checks if the media type is the json:api media type with no parameters other than 'ext' and 'profile'.
+func (m mediaType) isJSONAPI() bool {
+	if m.value != jsonapi.MimeType {
+		return false
+	}
+	for param := range m.params {
+		if param != "ext" && param != "profile" {
+			return false
+		}
+	}
+	return true
+}
+
+// profiles returns the space separated profile URIs stored in the 'profile' parameter.
+func (m mediaType) profiles() []string {
+	return strings.Fields(m.params["profile"])
+}
+
+// parseMediaTypes parses comma separated media types from given header value. Invalid media types are skipped.
+func parseMediaTypes(header string) []mediaType {
+	var mediaTypes []mediaType
+	for _, value := range strings.Split(header, ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(value)
+		if err != nil {
+			continue
+		}
+		// The quality factor is not a media type parameter.
+		delete(params, "q")
+		mediaTypes = append(mediaTypes, mediaType{value: mt, params: params})
+	}
+	return mediaTypes
+}
+
+// acceptsUTF8 checks if given 'Accept-Charset' header value allows the UTF-8 charset.
+// An empty header allows any charset. The explicit 'utf-8' charset takes precedence over the '*' wildcard.
+func acceptsUTF8(header string) bool {
+	if strings.TrimSpace(header) == "" {
+		return true
+	}
+	var hasWildcard, wildcardAcceptable bool
+	for _, value := range strings.Split(header, ",") {
+		params := strings.Split(value, ";")
+		charset := strings.ToLower(strings.TrimSpace(params[0]))
+		if charset != "utf-8" && charset != "utf8" && charset != "*" {
+			continue
+		}
+		acceptable := true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			// The charset with zero quality factor is not acceptable.
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil && q == 0 {
+				acceptable = false
+			}
+		}
+		if charset != "*" {
+			return acceptable
+		}
+		hasWildcard, wildcardAcceptable = true, acceptable
+	}
+	return hasWildcard && wildcardAcceptable
+}
@@ -0,0 +1,82 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+func TestMidAcceptCharset(t *testing.T) {
+	tests := []struct {
+		acceptCharset string
+		status        int
+	}{
+		{"", http.StatusOK},
+		{"utf-8", http.StatusOK},
+		{"UTF-8;q=0.5", http.StatusOK},
+		{"iso-8859-1", http.StatusNotAcceptable},
+		{"iso-8859-1, utf-8;q=0.1", http.StatusOK},
+		{"utf-8;q=0", http.StatusNotAcceptable},
+		{"*", http.StatusOK},
+		{"iso-8859-1, *;q=0.1", http.StatusOK},
+		{"*;q=0", http.StatusNotAcceptable},
+		{"utf-8;q=0, *", http.StatusNotAcceptable},
+	}
+	handler := MidAccept(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+		req.Header.Set("Accept", jsonapi.MimeType)
+		if tc.acceptCharset != "" {
+			req.Header.Set("Accept-Charset", tc.acceptCharset)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Errorf("Accept-Charset: '%s' expected status: %d, got: %d", tc.acceptCharset, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestMidRequestID(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		valid bool
+	}{
+		{"Empty", "", false},
+		{"Valid", "abc-123_DEF.456", true},
+		{"MaxLength", strings.Repeat("a", 128), true},
+		{"TooLong", strings.Repeat("a", 129), false},
+		{"ControlCharacter", "abc\x01def", false},
+		{"NonASCII", "abcądef", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var ctxID string
+			handler := MidRequestID(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				ctxID, _ = CtxRequestID(req.Context())
+				rw.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+			req.Header.Set(HeaderRequestID, tc.id)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(HeaderRequestID)
+			if id != ctxID {
+				t.Errorf("response id: '%s' differs from the context id: '%s'", id, ctxID)
+			}
+			if tc.valid && id != tc.id {
+				t.Errorf("expected the provided id: '%s', got: '%s'", tc.id, id)
+			}
+			if !tc.valid && (id == tc.id || len(id) != 32) {
+				t.Errorf("expected new generated id, got: '%s'", id)
+			}
+		})
+	}
+}
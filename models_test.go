@@ -0,0 +1,20 @@
+package jsonapi
+
+//go:generate neurogonesis models methods --single-file --format=goimports .
+
+// Author is the test model with the has many relationship.
+type Author struct {
+	ID    int `neuron:"type=primary"`
+	Name  string
+	Blogs []*Blog `neuron:"type=relation;foreign=AuthorID"`
+}
+
+// Blog is the test model with the belongs to relationship.
+type Blog struct {
+	ID       int `neuron:"type=primary"`
+	Title    string
+	Subtitle *string
+	Body     string
+	AuthorID int     `neuron:"type=foreign"`
+	Author   *Author `neuron:"type=relation;foreign=AuthorID"`
+}
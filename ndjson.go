@@ -0,0 +1,89 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// MimeTypeNDJSON is the newline-delimited JSON media type of the streamed list responses.
+const MimeTypeNDJSON = "application/x-ndjson"
+
+// acceptsNDJSON checks if the request 'Accept' header contains the NDJSON media type.
+func acceptsNDJSON(req *http.Request) bool {
+	for _, mediaType := range parseMediaTypes(req.Header.Get("Accept")) {
+		if mediaType.value == MimeTypeNDJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// midListAccept is the list endpoint accept middleware. If the AllowNDJSON option is set, the requests
+// accepting the NDJSON media type are passed through, otherwise it requires the json:api media type.
+func (a *API) midListAccept(next http.Handler) http.Handler {
+	jsonapiNext := MidAccept(next)
+	if !a.Options.AllowNDJSON {
+		return jsonapiNext
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if acceptsNDJSON(req) {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		jsonapiNext.ServeHTTP(rw, req)
+	})
+}
+
+// writeNDJSON streams the 'models' as newline-delimited JSON objects with the 'type', 'id' and the flattened
+// attributes from the 'fieldSet'. Each line is flushed, so that the clients could process the resources as they come.
+// The 'models' are the complete result of the list handler chain, thus the NDJSONMaxResults bounds the memory used
+// by the unpaginated lists.
+func (a *API) writeNDJSON(rw http.ResponseWriter, mStruct *mapping.ModelStruct, fieldSet mapping.FieldSet, models []mapping.Model) {
+	rw.Header().Set("Content-Type", MimeTypeNDJSON)
+	rw.WriteHeader(http.StatusOK)
+	flusher, canFlush := rw.(http.Flusher)
+	enc := json.NewEncoder(rw)
+	enc.SetEscapeHTML(false)
+	for _, model := range models {
+		line, err := ndjsonLine(mStruct, fieldSet, model)
+		if err != nil {
+			// The status is already written - the stream is ended at the faulty resource.
+			log.Errorf("[LIST][%s] marshaling NDJSON resource failed: %v", mStruct, err)
+			return
+		}
+		if err = enc.Encode(line); err != nil {
+			log.Debug2f("[LIST][%s] writing NDJSON resource failed: %v", mStruct, err)
+			return
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+// ndjsonLine gets the flattened object of the 'model' with its 'type', 'id' and the 'fieldSet' attributes.
+func ndjsonLine(mStruct *mapping.ModelStruct, fieldSet mapping.FieldSet, model mapping.Model) (map[string]interface{}, error) {
+	id, err := model.GetPrimaryKeyStringValue()
+	if err != nil {
+		return nil, err
+	}
+	line := map[string]interface{}{"type": mStruct.Collection(), "id": id}
+	fielder, ok := model.(mapping.Fielder)
+	if !ok {
+		return line, nil
+	}
+	for _, field := range fieldSet {
+		if field.Kind() != mapping.KindAttribute {
+			continue
+		}
+		value, err := fielder.GetFieldValue(field)
+		if err != nil {
+			return nil, err
+		}
+		line[field.NeuronName()] = value
+	}
+	return line, nil
+}
@@ -0,0 +1,56 @@
+package jsonapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// flushRecorder is the response recorder that records the number of lines written at each flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushedLines []int
+}
+
+// Flush implements http.Flusher interface.
+func (r *flushRecorder) Flush() {
+	r.flushedLines = append(r.flushedLines, bytes.Count(r.Body.Bytes(), []byte("\n")))
+	r.ResponseRecorder.Flush()
+}
+
+func TestWriteNDJSONFlushesLines(t *testing.T) {
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs := c.MustModelStruct(&Blog{})
+	title, ok := blogs.Attribute("Title")
+	if !ok {
+		t.Fatal("no title attribute")
+	}
+	models := []mapping.Model{&Blog{ID: 1, Title: "first"}, &Blog{ID: 2, Title: "second"}, &Blog{ID: 3, Title: "third"}}
+
+	a := &API{}
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	// The NDJSON list is written through the response header collector, as all the API responses.
+	handler := midResponseHeader(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		a.writeNDJSON(rw, blogs, mapping.FieldSet{title}, models)
+	}))
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/blogs", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != MimeTypeNDJSON {
+		t.Errorf("expected content type: '%s', got: '%s'", MimeTypeNDJSON, got)
+	}
+	if len(rec.flushedLines) != len(models) {
+		t.Fatalf("expected %d flushes, got: %d", len(models), len(rec.flushedLines))
+	}
+	for i, lines := range rec.flushedLines {
+		if lines != i+1 {
+			t.Errorf("flush: %d expected after line: %d, got after: %d lines", i, i+1, lines)
+		}
+	}
+}
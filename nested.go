@@ -0,0 +1,99 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+	"github.com/neuronlabs/neuron-extensions/server/http/middleware"
+	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+	"github.com/neuronlabs/neuron/server"
+)
+
+type nestedListCtxKey struct{}
+
+// nestedList is the nested collection list of the related resources of the parent resource.
+type nestedList struct {
+	// filter binds the listed resources to the parent resource.
+	filter filter.Filter
+	// path is the nested collection path used in the pagination links.
+	path string
+}
+
+// ctxNestedList gets the nested list stored in given context.
+func ctxNestedList(ctx context.Context) (*nestedList, bool) {
+	nested, ok := ctx.Value(nestedListCtxKey{}).(*nestedList)
+	return nested, ok
+}
+
+// listPath gets the path of the listed collection used in the list links.
+func (a *API) listPath(req *http.Request, mStruct *mapping.ModelStruct) string {
+	if nested, ok := ctxNestedList(req.Context()); ok {
+		return nested.path
+	}
+	return a.baseModelPath(mStruct)
+}
+
+// setNestedListRoute sets the nested collection route of the 'model' to-many 'relation' i.e. '/authors/:id/books'.
+// The route replaces the get related route and lists the related resources with the full list semantics.
+func (a *API) setNestedListRoute(router *httprouter.Router, model *mapping.ModelStruct, collection string, relation *mapping.StructField) {
+	endpointPath := fmt.Sprintf("/%s/:%s/%s", collection, a.idParam(model), relation.NeuronName())
+	if a.Options.PathPrefix != "/" {
+		endpointPath = a.Options.PathPrefix + endpointPath
+	}
+	relatedStruct := relation.Relationship().RelatedModelStruct()
+	endpoint := &server.Endpoint{
+		Path:        endpointPath,
+		HTTPMethod:  "GET",
+		QueryMethod: query.List,
+		ModelStruct: relatedStruct,
+		Relation:    relation,
+	}
+	a.Endpoints = append(a.Endpoints, endpoint)
+	chain := append(a.middlewares(), a.midListAccept, middleware.StoreIDFromParams(a.idParam(model)), httputil.MidStoreEndpoint(endpoint),
+		a.midTracing, a.midModelConcurrency, a.midDeprecation, a.midNestedList(model, relation))
+	if middlewarer, ok := a.handlers[relatedStruct].(server.ListMiddlewarer); ok {
+		chain = append(chain, middlewarer.ListMiddlewares()...)
+	}
+	log.Debugf("GET %s", endpointPath)
+	router.GET(endpointPath, httputil.Wrap(chain.Handle(a.handleList(relatedStruct))))
+}
+
+// midNestedList is the middleware that stores the nested list of the parent 'model' with the url id in the context.
+// If the parent resource doesn't exist the request is rejected with the '404 Not Found' status.
+func (a *API) midNestedList(model *mapping.ModelStruct, relation *mapping.StructField) server.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			id := httputil.CtxMustGetID(req.Context())
+			parent := mapping.NewModel(model)
+			if err := parent.SetPrimaryKeyStringValue(id); err != nil || parent.IsPrimaryKeyZero() {
+				log.Debugf("[LIST][%s][%s] Invalid URL id value: '%s'", model, relation, id)
+				a.marshalErrors(rw, req, 0, errInvalidID(model))
+				return
+			}
+			parentScope := query.NewScope(model)
+			parentScope.Filter(filter.New(model.Primary(), filter.OpEqual, parent.GetPrimaryKeyValue()))
+			count, err := database.Count(req.Context(), a.readDB(), parentScope)
+			if err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
+			if count == 0 {
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(model, id))
+				return
+			}
+			nested := &nestedList{
+				filter: filter.New(relation.Relationship().ForeignKey(), filter.OpEqual, parent.GetPrimaryKeyValue()),
+				path:   a.baseModelPath(model) + "/" + id + "/" + relation.NeuronName(),
+			}
+			next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), nestedListCtxKey{}, nested)))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// nullFilterValue is the literal filter value matching the null field values.
+const nullFilterValue = "null"
+
+// takeNullFilters removes the filters of the 'mStruct' nullable fields with the literal 'null' value from the query
+// 'values' and returns them as the 'is null' filters, i.e. 'filter[deletedAt]=null' or 'filter[deletedAt][$eq]=null'.
+// The not equal filters, i.e. 'filter[deletedAt][$ne]=null', are returned as the 'not null' filters.
+// Otherwise the parser would compare the field with the 'null' string. The 'null' filter of the attribute or foreign
+// key that could not store the null value is an invalid query parameter.
+func takeNullFilters(mStruct *mapping.ModelStruct, values url.Values) (filters []filter.Filter, err error) {
+	for key, value := range values {
+		if len(value) != 1 || value[0] != nullFilterValue || !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		// Split 'filter[collection][field][$op]' into its bracket segments.
+		segments := strings.Split(key[len("filter["):len(key)-1], "][")
+		op := filter.OpIsNull
+		if last := segments[len(segments)-1]; strings.HasPrefix(last, "$") {
+			switch last {
+			case filter.OpEqual.URLAlias:
+			case filter.OpNotEqual.URLAlias:
+				op = filter.OpNotNull
+			default:
+				continue
+			}
+			segments = segments[:len(segments)-1]
+		}
+		if len(segments) == 2 && segments[0] == mStruct.Collection() {
+			segments = segments[1:]
+		}
+		if len(segments) != 1 {
+			continue
+		}
+		field, ok := mStruct.Attribute(segments[0])
+		if !ok {
+			if field, ok = mStruct.ForeignKey(segments[0]); !ok {
+				// The unknown fields are reported by the parser.
+				continue
+			}
+		}
+		if !isNullable(field) {
+			return nil, errInvalidParameter(key, fmt.Sprintf("The field: '%s' is not nullable and cannot be filtered with the null value.", field.NeuronName()))
+		}
+		delete(values, key)
+		filters = append(filters, filter.New(field, op))
+	}
+	return filters, nil
+}
+
+// isNullable checks if the 'field' could store the null value. These are the pointer, slice and map fields,
+// the 'database/sql' null types, i.e. sql.NullString, and the foreign keys, which are null for the empty
+// belongs to relationships.
+func isNullable(field *mapping.StructField) bool {
+	if field.IsPtr() || field.IsSlice() || field.IsMap() || field.Kind() == mapping.KindForeignKey {
+		return true
+	}
+	t := field.ReflectField().Type
+	return t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null")
+}
@@ -0,0 +1,111 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/controller"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+func TestTakeNullFilters(t *testing.T) {
+	c := controller.NewDefault()
+	if err := c.RegisterModels(Neuron_Models...); err != nil {
+		t.Fatalf("registering models failed: %v", err)
+	}
+	blogs := c.MustModelStruct(&Blog{})
+	subtitle := blogs.MustFieldByName("Subtitle")
+	title := blogs.MustFieldByName("Title")
+	authorID, ok := blogs.ForeignKey("AuthorID")
+	if !ok {
+		t.Fatal("no author foreign key")
+	}
+
+	t.Run("Nullable", func(t *testing.T) {
+		for key, op := range map[string]*filter.Operator{
+			"filter[" + subtitle.NeuronName() + "]":             filter.OpIsNull,
+			"filter[blogs][" + subtitle.NeuronName() + "][$eq]": filter.OpIsNull,
+			"filter[" + subtitle.NeuronName() + "][$ne]":        filter.OpNotNull,
+			"filter[" + authorID.NeuronName() + "]":             filter.OpIsNull,
+			"filter[blogs][" + authorID.NeuronName() + "][$ne]": filter.OpNotNull,
+		} {
+			values := url.Values{key: {"null"}}
+			filters, err := takeNullFilters(blogs, values)
+			if err != nil {
+				t.Fatalf("%s: taking null filters failed: %v", key, err)
+			}
+			if len(filters) != 1 {
+				t.Fatalf("%s: expected single filter, got: %d", key, len(filters))
+			}
+			simple, ok := filters[0].(filter.Simple)
+			if !ok || simple.Operator != op || (simple.StructField != subtitle && simple.StructField != authorID) {
+				t.Errorf("%s: unexpected filter: %v", key, filters[0])
+			}
+			if _, ok := values[key]; ok {
+				t.Errorf("%s: null filter not removed from the query values", key)
+			}
+		}
+	})
+
+	t.Run("NotNullValue", func(t *testing.T) {
+		key := "filter[" + subtitle.NeuronName() + "]"
+		values := url.Values{key: {"nullable"}}
+		filters, err := takeNullFilters(blogs, values)
+		if err != nil {
+			t.Fatalf("taking null filters failed: %v", err)
+		}
+		if len(filters) != 0 || len(values[key]) != 1 {
+			t.Errorf("the non null filter value is taken: %v", filters)
+		}
+	})
+
+	t.Run("NonNullable", func(t *testing.T) {
+		key := "filter[" + title.NeuronName() + "]"
+		_, err := takeNullFilters(blogs, url.Values{key: {"null"}})
+		if err == nil {
+			t.Fatal("expected error for the non nullable field")
+		}
+		cErr, ok := err.(*codec.Error)
+		if !ok {
+			t.Fatalf("expected codec error, got: %T", err)
+		}
+		if cErr.Status != "400" {
+			t.Errorf("expected status: 400, got: %s", cErr.Status)
+		}
+		if cErr.Meta["parameter"] != key {
+			t.Errorf("expected parameter: '%s' in the error meta, got: %v", key, cErr.Meta["parameter"])
+		}
+	})
+}
+
+func TestHandleListNullFilter(t *testing.T) {
+	subtitle := "subtitle"
+	ta := newTestAPI(t, nil)
+	ta.storeBlogs(&Blog{ID: 1, Title: "title", AuthorID: 2}, &Blog{ID: 2, Title: "title", Subtitle: &subtitle, AuthorID: 2})
+
+	for target, expected := range map[string]string{
+		"/blogs?filter[subtitle]=null":             "1",
+		"/blogs?filter[blogs][subtitle][$ne]=null": "2",
+		"/authors/2/blogs?filter[subtitle]=null":   "1",
+	} {
+		rec := ta.serve(http.MethodGet, target, "", "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		var resources []object
+		if err := json.Unmarshal(decodeDocument(t, rec)["data"], &resources); err != nil {
+			t.Fatalf("%s: decoding primary data failed: %v", target, err)
+		}
+		if len(resources) != 1 || resources[0].id() != expected {
+			t.Errorf("%s: expected only the blog: %s, got: %v", target, expected, resources)
+		}
+	}
+
+	// The title is not nullable - the null filter is rejected, instead of comparing it with the 'null' string.
+	rec := ta.serve(http.MethodGet, "/blogs?filter[title]=null", "", "Accept", jsonapi.MimeType)
+	expectStatus(t, rec, http.StatusBadRequest)
+}
@@ -1,7 +1,13 @@
 package jsonapi
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/database"
 	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/server"
 )
 
@@ -11,30 +17,254 @@ type ModelHandler struct {
 	Handler interface{}
 }
 
+// ModelRelations is a struct that matches given Model with the names of its relations.
+type ModelRelations struct {
+	Model     mapping.Model
+	Relations []string
+}
+
+// ModelFields is a struct that matches given Model with the names of its attributes and relations.
+type ModelFields struct {
+	Model  mapping.Model
+	Fields []string
+}
+
+// ModelAliases is a struct that matches given Model with the aliases of its collection.
+type ModelAliases struct {
+	Model   mapping.Model
+	Aliases []string
+}
+
+// DefaultMaxResponseBytes is the default maximum size of the marshaled response document.
+const DefaultMaxResponseBytes = 64 << 20
+
+// OutOfRangePagination defines the list endpoints behavior for the pagination offset beyond the total number of resources.
+type OutOfRangePagination int
+
+const (
+	// OutOfRangeEmpty returns the empty primary data with the pagination links. This is the default behavior.
+	OutOfRangeEmpty OutOfRangePagination = iota
+	// OutOfRangeError rejects the request with the '400 Bad Request' status.
+	OutOfRangeError
+)
+
+// ModelIDParam is a struct that matches given Model with the name of its id url parameter.
+type ModelIDParam struct {
+	Model mapping.Model
+	Param string
+}
+
+// ModelPageSize is a struct that matches given Model with its default page size.
+type ModelPageSize struct {
+	Model    mapping.Model
+	PageSize int
+}
+
+// DeprecatedEndpoint is a struct that matches given Model endpoint with the query method and the time of its removal.
+type DeprecatedEndpoint struct {
+	Model       mapping.Model
+	QueryMethod query.Method
+	Sunset      time.Time
+}
+
 // Options is a structure that defines json:api settings.
 type Options struct {
 	// PathPrefix is the path prefix used for all endpoints within given API.
 	PathPrefix string
+	// ReadDB is the database (i.e. read replica) used by the get, list, get related and get relationship endpoints.
+	// If not set, all the endpoints use the server database.
+	ReadDB database.DB
 	// DefaultPageSize defines default PageSize for the list endpoints.
 	DefaultPageSize int
+	// FullPaginationLinks makes the list endpoints always marshal all the pagination link members: 'first', 'last',
+	// 'prev' and 'next', with null values for the links not applicable (i.e. when the list is not paginated).
+	FullPaginationLinks bool
+	// PaginationOutOfRange defines the list endpoints behavior for the pagination offset beyond the total
+	// number of resources. By default the empty primary data is returned.
+	PaginationOutOfRange OutOfRangePagination
+	// ListDefaultFields are the model default fieldsets of the list endpoints, used if the client doesn't provide
+	// the sparse fieldset 'fields[type]' parameter. By default all the attributes and relations are marshaled.
+	ListDefaultFields []ModelFields
+	// GetDefaultFields are the model default fieldsets of the get endpoints, used if the client doesn't provide
+	// the sparse fieldset 'fields[type]' parameter. By default all the attributes and relations are marshaled.
+	GetDefaultFields []ModelFields
+	// ModelPageSizes are the model default page sizes overriding the DefaultPageSize for their list endpoints.
+	// Zero page size disables the default pagination for the model.
+	ModelPageSizes []ModelPageSize
+	// AllowNDJSON allows the list endpoints to stream the resources as newline-delimited JSON objects with
+	// flattened attributes, if the request accepts the 'application/x-ndjson' media type.
+	// Such lists are not paginated by default, but limited by the NDJSONMaxResults.
+	AllowNDJSON bool
+	// NDJSONMaxResults is the maximum number of resources streamed in a single NDJSON list response,
+	// if no pagination was provided by the client. Zero value means no limit.
+	NDJSONMaxResults int
+	// AllowRangePagination allows the list endpoints to be paginated with the 'Range: items=first-last' header,
+	// if no pagination query parameters are provided. Such responses have the '206 Partial Content' status
+	// and the 'Content-Range' header with the total number of resources.
+	AllowRangePagination bool
+	// DisableIncludes disables the 'include' parameter of the read requests from the API start.
+	// The includes could be toggled at runtime with the API SetIncludesDisabled method.
+	DisableIncludes bool
+	// DropDisabledIncludes silently drops the 'include' parameter while the includes are disabled,
+	// instead of rejecting the request with the '400 Bad Request' status.
+	DropDisabledIncludes bool
+	// StrictIncludeFields rejects the read requests which include a relationship excluded by the sparse fieldset
+	// of its model (i.e. 'include=author&fields[books]=title') with the '400 Bad Request' status. By default
+	// the include wins and the relationship is added to the fieldset.
+	StrictIncludeFields bool
+	// MaxIncludeBreadth is the maximum number of distinct top-level relationships included in a single read request.
+	// The requests exceeding the limit are rejected with '400 Bad Request' status. Zero value means no limit.
+	MaxIncludeBreadth int
+	// PrettyPrint indents the marshaled response documents. It is disabled by default for the payload size.
+	PrettyPrint bool
+	// AllowDebugPretty allows the clients to indent the response documents of a single request with the
+	// 'debug-pretty' query parameter. It should not be set in production.
+	AllowDebugPretty bool
+	// DisableHTMLEscape disables escaping of the HTML characters '<', '>' and '&' in the marshaled response
+	// documents (i.e. for the attributes with the URLs).
+	DisableHTMLEscape bool
+	// MaxResponseBytes is the maximum size of the marshaled response document. The responses exceeding the limit
+	// are replaced with the '413 Request Entity Too Large' error. Zero value means no limit.
+	// By default it is set to DefaultMaxResponseBytes.
+	MaxResponseBytes int
+	// MaxRelationshipMembers is the maximum number of relationship members provided in a single
+	// relationship insert, update or delete request. Zero value means no limit.
+	MaxRelationshipMembers int
+	// RejectEmptyRelationshipBody makes the relationship insert and update endpoints reject the requests
+	// with an empty body with the '400 Bad Request' status, before the body is unmarshaled.
+	RejectEmptyRelationshipBody bool
+	// IncludeUnfilteredTotal adds the total number of the collection resources, regardless of the client filters,
+	// into the paginated list response meta 'unfilteredTotal' member. The default filters of the model handler still
+	// apply. It requires an additional count query.
+	IncludeUnfilteredTotal bool
+	// EchoQueryMeta adds the sorting, filters and pagination applied by the server (including the defaults)
+	// into the list response meta 'query' member.
+	EchoQueryMeta bool
+	// MaxConcurrentPerModel is the maximum number of concurrent requests handled for a single model.
+	// The requests exceeding the limit are refused with '503 Service Unavailable' status. Zero value means no limit.
+	MaxConcurrentPerModel int
 	// NoContentOnCreate allows to set the flag for the models with client generated id to return no content.
 	NoContentOnInsert bool
 	// StrictFieldsMode defines if the during unmarshal process the query should strictly check
 	// if all the fields are well known to given model.
 	StrictUnmarshal bool
+	// RejectUnknownParams makes the read endpoints reject the requests with query parameters that are not
+	// recognized json:api parameters (i.e. misspelled 'pge[size]' or 'inclde').
+	RejectUnknownParams bool
 	// IncludeNestedLimit is a maximum value for nested includes (i.e. IncludeNestedLimit = 1
 	// allows ?include=posts.comments but does not allow ?include=posts.comments.author)
 	IncludeNestedLimit int
+	// MaxFilterDepth is a maximum number of relationships in the filter field path (i.e. MaxFilterDepth = 1
+	// allows filter[posts.title] but does not allow filter[posts.comments.body]). Zero value allows only the direct
+	// model fields to be filtered. Negative value means no limit.
+	MaxFilterDepth int
+	// MaxSortDepth is a maximum number of relationships in the sort field path (i.e. MaxSortDepth = 1
+	// allows sort=posts.title but does not allow sort=posts.comments.body). Zero value allows only the direct
+	// model fields to be sorted. Negative value means no limit.
+	MaxSortDepth int
+	// MaxSortFields is the maximum number of fields in the 'sort' parameter. The requests exceeding the limit
+	// are rejected with '400 Bad Request' status. Zero value means no limit.
+	MaxSortFields int
 	// FilterValueLimit is a maximum length of the filter values
 	FilterValueLimit int
 	// MarshalLinks is the default behavior for marshaling the resource links into the handler responses.
 	PayloadLinks bool
+	// IdempotentDelete makes the delete endpoint return '204 No Content' status when the resource doesn't exist.
+	IdempotentDelete bool
+	// LocaleField is the name of the model attribute storing the resource locale. If set, the get and list
+	// endpoints set the 'Content-Language' header to the locale of the returned resources, unless their locales differ.
+	// The models without such attribute are not affected.
+	LocaleField string
+	// ForeignKeyLinkage makes the get and list endpoints marshal the linkage of the belongs to relationships,
+	// that were not included by the client, using their loaded foreign keys, without an additional query.
+	ForeignKeyLinkage bool
+	// OmitEmptyRelationships removes the empty 'relationships' member from the marshaled resources,
+	// i.e. when the client requested only the attributes.
+	OmitEmptyRelationships bool
+	// StableIncludedOrder makes the 'included' resources sorted by their type and id, so that the responses
+	// are byte-stable across the requests.
+	StableIncludedOrder bool
+	// DirectRelatedLinks makes the get and list endpoints set the 'related' link of the to-one relationships
+	// with known linkage to the related resource url, i.e. '/authors/1' instead of '/books/1/author'.
+	DirectRelatedLinks bool
+	// ExplicitEmptyLinkage makes the get, get related and list endpoints marshal the requested to-many relationships
+	// with no members as an explicit empty linkage - 'data: []'.
+	ExplicitEmptyLinkage bool
+	// RelationshipChangedMeta adds the 'changed' flag into the update relationship response meta, which defines
+	// if the relationship members were changed by the request.
+	RelationshipChangedMeta bool
+	// PreviousLinkage allows the relationship mutation endpoints to return the relationship linkage before the change
+	// in the 'meta.previous' member, when requested with the 'Prefer: previous-linkage' header.
+	PreviousLinkage bool
+	// ReturnGoneForDeleted makes the get endpoint return '410 Gone' status for the soft-deleted resources.
+	ReturnGoneForDeleted bool
+	// EnableETag enables the ETag computation and 'If-None-Match' conditional requests on the list endpoints.
+	// It also enables the relationship linkage ETags on the get relationship endpoints and the 'If-Match'
+	// conditional requests on the relationship mutation endpoints.
+	EnableETag bool
+	// ETagSalt is mixed into each computed ETag (i.e. the build version), so that a new deployment
+	// invalidates the ETags cached by the clients.
+	ETagSalt string
+	// SingleFlightReads makes the concurrent identical get and list requests share a single handler execution.
+	// The shared execution is not canceled by the cancellation of any of the requests.
+	SingleFlightReads bool
+	// Profiles are the json:api profile URIs supported by the API. Requested profiles that are not listed
+	// here are ignored, the applied ones are echoed in the response 'Content-Type' profile parameter.
+	Profiles []string
+	// DescribedBy gets the URL of the schema document describing the model, set as the 'links.describedby'
+	// member of the responses with the model primary data. An empty URL suppresses the link for the model.
+	DescribedBy func(mStruct *mapping.ModelStruct) string
+	// ErrorStatusMapper is the function consulted first for the status of the error responses.
+	// If it returns true, its status is used, otherwise the status is resolved from the mapped json:api errors.
+	ErrorStatusMapper func(err error) (int, bool)
+	// HeaderScopeEnricher is the function called in the list, get and get related endpoints just after the query
+	// parameters are parsed. It allows to translate the request headers (i.e. feature flags) into the filters
+	// or field restrictions of the scope 's'. An error returned by the function is marshaled as the response.
+	HeaderScopeEnricher func(req *http.Request, s *query.Scope) error
+	// ResponsePostProcessor is the function called on each successful response payload just before it is marshaled.
+	// It allows to mutate the outgoing document, an error returned by the function results in '500 Internal Server Error'.
+	ResponsePostProcessor func(payload *codec.Payload, req *http.Request) error
+	// AllowMethodOverride allows the POST requests with the 'X-HTTP-Method-Override' header to be routed to the
+	// PATCH or DELETE endpoints (i.e. for the clients behind the proxies blocking these methods).
+	AllowMethodOverride bool
+	// IndexRoute enables the route at the PathPrefix that lists all the collections registered in the API.
+	IndexRoute bool
 	// Middlewares are global middlewares added to each endpoint in the given API.
 	Middlewares server.MiddlewareChain
 	// DefaultHandlerModels are the models assigned to the default API handler.
 	DefaultHandlerModels []mapping.Model
 	// ModelHandlers are the models with their paired API handlers.
 	ModelHandlers []ModelHandler
+	// ReadOnlyRelationships are the model relations for which the relationship mutation routes are not registered.
+	// If no relation names are provided, all relations of given model are read-only.
+	ReadOnlyRelationships []ModelRelations
+	// DeprecatedEndpoints are the model endpoints which responses contain the 'Deprecation' header
+	// and the 'Sunset' header, if its time is set.
+	DeprecatedEndpoints []DeprecatedEndpoint
+	// RefetchAfterInsert refetches the inserted resource with all its fields and relations, so that the insert
+	// response contains the values set by the repository (i.e. the database defaults), at the cost of an additional query.
+	RefetchAfterInsert bool
+	// ReplaceToOneRelationship allows the insert relationship requests to replace the current member of the to-one
+	// relationship. By default inserting a member into the to-one relationship that already has other member is
+	// rejected with the '409 Conflict' status.
+	ReplaceToOneRelationship bool
+	// ValidateBelongsToExists checks if the resources referenced by the belongs to relationships of the inserted or
+	// updated resource exists, within the same transaction as the write. The missing resources are rejected with
+	// the '422 Unprocessable Entity' status, instead of the foreign key violation of the repository.
+	ValidateBelongsToExists bool
+	// Tracer traces the API endpoints requests. Each request of the endpoint is traced with a span named by the
+	// endpoint HTTP method, collection and query method. Nil value disables tracing.
+	Tracer Tracer
+	// NestedRoutes are the model has many relations which related endpoints are replaced with the nested collection
+	// routes, i.e. '/authors/:id/books', that list the related resources with the full list semantics.
+	NestedRoutes []ModelRelations
+	// IDParams are the model id url parameter names used in the route patterns instead of the default 'id',
+	// i.e. '/books/:bookId'.
+	IDParams []ModelIDParam
+	// CollectionAliases are the additional (i.e. legacy) collection paths routed to the model endpoints.
+	// The links are always generated using the model's collection. The sparse fieldsets could be provided
+	// either for the collection or any of its aliases, i.e. 'fields[alias]'.
+	CollectionAliases []ModelAliases
 }
 
 type Option func(o *Options)
@@ -47,6 +277,13 @@ func WithPathPrefix(path string) Option {
 	}
 }
 
+// WithReadDB is an option that sets the database (i.e. read replica) used by the read endpoints.
+func WithReadDB(db database.DB) Option {
+	return func(o *Options) {
+		o.ReadDB = db
+	}
+}
+
 // WithDefaultPageSize is an option that sets the default page size.
 func WithDefaultPageSize(pageSize int) Option {
 	return func(o *Options) {
@@ -54,6 +291,175 @@ func WithDefaultPageSize(pageSize int) Option {
 	}
 }
 
+// WithFullPaginationLinks is an option that makes the list endpoints always marshal all the pagination link members.
+func WithFullPaginationLinks() Option {
+	return func(o *Options) {
+		o.FullPaginationLinks = true
+	}
+}
+
+// WithPaginationOutOfRange is an option that sets the list endpoints behavior for the pagination offset
+// beyond the total number of resources.
+func WithPaginationOutOfRange(mode OutOfRangePagination) Option {
+	return func(o *Options) {
+		o.PaginationOutOfRange = mode
+	}
+}
+
+// WithModelDefaultPageSize is an option that sets the default page size for the model list endpoint,
+// overriding the DefaultPageSize.
+func WithModelDefaultPageSize(model mapping.Model, pageSize int) Option {
+	return func(o *Options) {
+		o.ModelPageSizes = append(o.ModelPageSizes, ModelPageSize{Model: model, PageSize: pageSize})
+	}
+}
+
+// WithListDefaultFields is an option that sets the 'model' default fieldset of the list endpoint,
+// used if the client doesn't provide the sparse fieldset.
+func WithListDefaultFields(model mapping.Model, fields ...string) Option {
+	return func(o *Options) {
+		o.ListDefaultFields = append(o.ListDefaultFields, ModelFields{Model: model, Fields: fields})
+	}
+}
+
+// WithGetDefaultFields is an option that sets the 'model' default fieldset of the get endpoint,
+// used if the client doesn't provide the sparse fieldset.
+func WithGetDefaultFields(model mapping.Model, fields ...string) Option {
+	return func(o *Options) {
+		o.GetDefaultFields = append(o.GetDefaultFields, ModelFields{Model: model, Fields: fields})
+	}
+}
+
+// WithIncludeUnfilteredTotal is an option that adds the unfiltered total number of resources into
+// the paginated list response meta.
+func WithIncludeUnfilteredTotal() Option {
+	return func(o *Options) {
+		o.IncludeUnfilteredTotal = true
+	}
+}
+
+// WithEchoQueryMeta is an option that adds the applied list query into the list response meta.
+func WithEchoQueryMeta() Option {
+	return func(o *Options) {
+		o.EchoQueryMeta = true
+	}
+}
+
+// WithRangePagination is an option that allows the list endpoints to be paginated with the 'Range' header.
+func WithRangePagination() Option {
+	return func(o *Options) {
+		o.AllowRangePagination = true
+	}
+}
+
+// WithRejectEmptyRelationshipBody is an option that makes the relationship insert and update endpoints
+// reject the requests with an empty body.
+func WithRejectEmptyRelationshipBody() Option {
+	return func(o *Options) {
+		o.RejectEmptyRelationshipBody = true
+	}
+}
+
+// WithNDJSON is an option that allows the list endpoints to stream the resources as newline-delimited JSON,
+// limited to 'maxResults' resources if no pagination was provided. Zero 'maxResults' means no limit.
+func WithNDJSON(maxResults int) Option {
+	return func(o *Options) {
+		o.AllowNDJSON = true
+		o.NDJSONMaxResults = maxResults
+	}
+}
+
+// WithDisableIncludes is an option that disables the 'include' parameter of the read requests.
+// If 'drop' is true, the parameter is silently dropped instead of rejecting the request.
+func WithDisableIncludes(drop bool) Option {
+	return func(o *Options) {
+		o.DisableIncludes = true
+		o.DropDisabledIncludes = drop
+	}
+}
+
+// WithStrictIncludeFields is an option that rejects the read requests which include a relationship excluded
+// by the sparse fieldset of its model.
+func WithStrictIncludeFields() Option {
+	return func(o *Options) {
+		o.StrictIncludeFields = true
+	}
+}
+
+// WithPrettyPrint is an option that indents the marshaled response documents.
+func WithPrettyPrint() Option {
+	return func(o *Options) {
+		o.PrettyPrint = true
+	}
+}
+
+// WithDebugPretty is an option that allows indenting the response documents of a single request with the
+// 'debug-pretty' query parameter.
+func WithDebugPretty() Option {
+	return func(o *Options) {
+		o.AllowDebugPretty = true
+	}
+}
+
+// WithDisableHTMLEscape is an option that disables escaping of the HTML characters in the response documents.
+func WithDisableHTMLEscape() Option {
+	return func(o *Options) {
+		o.DisableHTMLEscape = true
+	}
+}
+
+// WithMaxIncludeBreadth is an option that limits the number of distinct top-level relationships included
+// in a single read request.
+func WithMaxIncludeBreadth(max int) Option {
+	return func(o *Options) {
+		o.MaxIncludeBreadth = max
+	}
+}
+
+// WithMaxResponseBytes is an option that sets the maximum size of the marshaled response document.
+// Zero value means no limit.
+func WithMaxResponseBytes(max int) Option {
+	return func(o *Options) {
+		o.MaxResponseBytes = max
+	}
+}
+
+// WithMaxRelationshipMembers is an option that limits the number of relationship members provided
+// in a single relationship insert, update or delete request.
+func WithMaxRelationshipMembers(max int) Option {
+	return func(o *Options) {
+		o.MaxRelationshipMembers = max
+	}
+}
+
+// WithMaxFilterDepth is an option that sets the maximum number of relationships in the filter field path.
+func WithMaxFilterDepth(depth int) Option {
+	return func(o *Options) {
+		o.MaxFilterDepth = depth
+	}
+}
+
+// WithMaxSortDepth is an option that sets the maximum number of relationships in the sort field path.
+func WithMaxSortDepth(depth int) Option {
+	return func(o *Options) {
+		o.MaxSortDepth = depth
+	}
+}
+
+// WithMaxSortFields is an option that limits the number of fields in the 'sort' parameter.
+func WithMaxSortFields(max int) Option {
+	return func(o *Options) {
+		o.MaxSortFields = max
+	}
+}
+
+// WithMaxConcurrentPerModel is an option that limits the number of concurrent requests handled for a single model.
+func WithMaxConcurrentPerModel(max int) Option {
+	return func(o *Options) {
+		o.MaxConcurrentPerModel = max
+	}
+}
+
 // WithStrictUnmarshal sets the api option for strict codec unmarshal.
 func WithStrictUnmarshal() Option {
 	return func(o *Options) {
@@ -61,6 +467,14 @@ func WithStrictUnmarshal() Option {
 	}
 }
 
+// WithRejectUnknownParams is an option that makes the read endpoints respond with '400 Bad Request'
+// for the requests containing unknown query parameters.
+func WithRejectUnknownParams() Option {
+	return func(o *Options) {
+		o.RejectUnknownParams = true
+	}
+}
+
 // WithPayloadLinks
 func WithPayloadLinks(payloadLinks bool) Option {
 	return func(o *Options) {
@@ -75,6 +489,157 @@ func WithMiddlewares(middlewares ...server.Middleware) Option {
 	}
 }
 
+// WithIdempotentDelete is an option that makes the delete endpoint return '204 No Content' status
+// for the non-existing resources, instead of '404 Not Found'.
+func WithIdempotentDelete() Option {
+	return func(o *Options) {
+		o.IdempotentDelete = true
+	}
+}
+
+// WithErrorStatusMapper is an option that sets the function overriding the status of the error responses.
+func WithErrorStatusMapper(mapper func(err error) (int, bool)) Option {
+	return func(o *Options) {
+		o.ErrorStatusMapper = mapper
+	}
+}
+
+// WithDescribedBy is an option that sets the function getting the URL of the model schema document,
+// set as the 'links.describedby' member of the responses.
+func WithDescribedBy(describedBy func(mStruct *mapping.ModelStruct) string) Option {
+	return func(o *Options) {
+		o.DescribedBy = describedBy
+	}
+}
+
+// WithLocaleField is an option that sets the name of the model attribute storing the resource locale,
+// used as the 'Content-Language' header of the get and list responses.
+func WithLocaleField(field string) Option {
+	return func(o *Options) {
+		o.LocaleField = field
+	}
+}
+
+// WithForeignKeyLinkage is an option that makes the get and list endpoints marshal the belongs to
+// relationships linkage using their foreign keys, without an additional query.
+func WithForeignKeyLinkage() Option {
+	return func(o *Options) {
+		o.ForeignKeyLinkage = true
+	}
+}
+
+// WithOmitEmptyRelationships is an option that removes the empty 'relationships' member from the marshaled resources.
+func WithOmitEmptyRelationships() Option {
+	return func(o *Options) {
+		o.OmitEmptyRelationships = true
+	}
+}
+
+// WithStableIncludedOrder is an option that makes the 'included' resources sorted by their type and id.
+func WithStableIncludedOrder() Option {
+	return func(o *Options) {
+		o.StableIncludedOrder = true
+	}
+}
+
+// WithDirectRelatedLinks is an option that makes the to-one relationships 'related' links point directly
+// to the related resource url.
+func WithDirectRelatedLinks() Option {
+	return func(o *Options) {
+		o.DirectRelatedLinks = true
+	}
+}
+
+// WithExplicitEmptyLinkage is an option that makes the get, get related and list endpoints marshal
+// the requested to-many relationships with no members as an explicit empty linkage.
+func WithExplicitEmptyLinkage() Option {
+	return func(o *Options) {
+		o.ExplicitEmptyLinkage = true
+	}
+}
+
+// WithRelationshipChangedMeta is an option that adds the 'changed' flag into the update relationship response meta.
+func WithRelationshipChangedMeta() Option {
+	return func(o *Options) {
+		o.RelationshipChangedMeta = true
+	}
+}
+
+// WithPreviousLinkage is an option that allows the relationship mutation endpoints to return the relationship
+// linkage before the change, when requested with the 'Prefer: previous-linkage' header.
+func WithPreviousLinkage() Option {
+	return func(o *Options) {
+		o.PreviousLinkage = true
+	}
+}
+
+// WithReturnGoneForDeleted is an option that makes the get endpoint return '410 Gone' status for the
+// soft-deleted resources, instead of '404 Not Found'.
+func WithReturnGoneForDeleted() Option {
+	return func(o *Options) {
+		o.ReturnGoneForDeleted = true
+	}
+}
+
+// WithETag is an option that enables the ETag headers and the conditional requests.
+func WithETag() Option {
+	return func(o *Options) {
+		o.EnableETag = true
+	}
+}
+
+// WithETagSalt is an option that sets the 'salt' mixed into each computed ETag, i.e. the build version.
+func WithETagSalt(salt string) Option {
+	return func(o *Options) {
+		o.ETagSalt = salt
+	}
+}
+
+// WithSingleFlightReads is an option that makes the concurrent identical get and list requests
+// share a single handler execution and its response.
+func WithSingleFlightReads() Option {
+	return func(o *Options) {
+		o.SingleFlightReads = true
+	}
+}
+
+// WithProfiles is an option that sets the json:api profiles supported by the API.
+func WithProfiles(profiles ...string) Option {
+	return func(o *Options) {
+		o.Profiles = append(o.Profiles, profiles...)
+	}
+}
+
+// WithHeaderScopeEnricher is an option that sets the function enriching the read query scopes from the request headers.
+func WithHeaderScopeEnricher(enricher func(req *http.Request, s *query.Scope) error) Option {
+	return func(o *Options) {
+		o.HeaderScopeEnricher = enricher
+	}
+}
+
+// WithMethodOverride is an option that allows the POST requests with the 'X-HTTP-Method-Override' header
+// to be routed to the PATCH or DELETE endpoints.
+func WithMethodOverride() Option {
+	return func(o *Options) {
+		o.AllowMethodOverride = true
+	}
+}
+
+// WithResponsePostProcessor is an option that sets the function called on each successful response payload
+// just before it is marshaled.
+func WithResponsePostProcessor(postProcessor func(payload *codec.Payload, req *http.Request) error) Option {
+	return func(o *Options) {
+		o.ResponsePostProcessor = postProcessor
+	}
+}
+
+// WithIndexRoute is an option that enables the API root route listing all registered collections.
+func WithIndexRoute() Option {
+	return func(o *Options) {
+		o.IndexRoute = true
+	}
+}
+
 // WithNoContentOnInsert is an option that tells API to return http.StatusNoContent if an endpoint
 // allows client generated primary key, and given insert is accepted.
 func WithNoContentOnInsert() Option {
@@ -96,3 +661,73 @@ func WithModelHandler(model mapping.Model, handler interface{}) Option {
 		o.ModelHandlers = append(o.ModelHandlers, ModelHandler{Model: model, Handler: handler})
 	}
 }
+
+// WithReadOnlyRelationships is an option that disables the relationship mutation routes (POST, PATCH and DELETE)
+// for the model 'relations'. If no relations are provided, all relations of given model are read-only.
+func WithReadOnlyRelationships(model mapping.Model, relations ...string) Option {
+	return func(o *Options) {
+		o.ReadOnlyRelationships = append(o.ReadOnlyRelationships, ModelRelations{Model: model, Relations: relations})
+	}
+}
+
+// WithRefetchAfterInsert is an option that refetches the inserted resource, so that the insert response contains
+// the values set by the repository.
+func WithRefetchAfterInsert() Option {
+	return func(o *Options) {
+		o.RefetchAfterInsert = true
+	}
+}
+
+// WithReplaceToOneRelationship is an option that allows the insert relationship requests to replace the current
+// member of the to-one relationship.
+func WithReplaceToOneRelationship() Option {
+	return func(o *Options) {
+		o.ReplaceToOneRelationship = true
+	}
+}
+
+// WithValidateBelongsToExists is an option that checks if the resources referenced by the belongs to relationships
+// of the inserted or updated resource exists.
+func WithValidateBelongsToExists() Option {
+	return func(o *Options) {
+		o.ValidateBelongsToExists = true
+	}
+}
+
+// WithTracer is an option that traces the API endpoints requests with given 'tracer'.
+func WithTracer(tracer Tracer) Option {
+	return func(o *Options) {
+		o.Tracer = tracer
+	}
+}
+
+// WithNestedRoutes is an option that replaces the related endpoints of the model has many 'relations'
+// with the nested collection routes, listing the related resources with the full list semantics.
+func WithNestedRoutes(model mapping.Model, relations ...string) Option {
+	return func(o *Options) {
+		o.NestedRoutes = append(o.NestedRoutes, ModelRelations{Model: model, Relations: relations})
+	}
+}
+
+// WithIDParam is an option that sets the name of the model id url parameter used in its route patterns.
+func WithIDParam(model mapping.Model, param string) Option {
+	return func(o *Options) {
+		o.IDParams = append(o.IDParams, ModelIDParam{Model: model, Param: param})
+	}
+}
+
+// WithCollectionAliases is an option that sets the additional collection paths (i.e. legacy names)
+// routed to the same endpoints as the model's collection.
+func WithCollectionAliases(model mapping.Model, aliases ...string) Option {
+	return func(o *Options) {
+		o.CollectionAliases = append(o.CollectionAliases, ModelAliases{Model: model, Aliases: aliases})
+	}
+}
+
+// WithDeprecatedEndpoint is an option that marks the model endpoints with given query 'method' as deprecated.
+// The responses contain the 'Deprecation: true' header and the 'Sunset' header with given time, if it is not zero.
+func WithDeprecatedEndpoint(model mapping.Model, method query.Method, sunset time.Time) Option {
+	return func(o *Options) {
+		o.DeprecatedEndpoints = append(o.DeprecatedEndpoints, DeprecatedEndpoint{Model: model, QueryMethod: method, Sunset: sunset})
+	}
+}
@@ -0,0 +1,97 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/neuronlabs/neuron/codec"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+// paginationLinks creates the self, first, prev, next and last links for the resource at given 'path'
+// paginated with 'pagination' over the 'total' number of values.
+func (a *API) paginationLinks(req *http.Request, path string, pagination *query.Pagination, total int64) (*codec.PaginationLinks, error) {
+	links := &codec.PaginationLinks{Total: total}
+	link := func(p *query.Pagination) string {
+		temp, pageBased := a.queryWithoutPagination(req)
+		jsonapi.FormatPagination(p, temp, pageBased)
+		sb := strings.Builder{}
+		sb.WriteString(path)
+		sb.WriteRune('?')
+		sb.WriteString(temp.Encode())
+		return sb.String()
+	}
+	links.Self = link(pagination)
+
+	next, err := pagination.Next(total)
+	if err != nil {
+		return nil, err
+	}
+	if next != pagination {
+		links.Next = link(next)
+	}
+
+	prev, err := pagination.Previous()
+	if err != nil {
+		return nil, err
+	}
+	if prev != pagination {
+		links.Prev = link(prev)
+	}
+
+	last, err := pagination.Last(total)
+	if err != nil {
+		return nil, err
+	}
+	// The last page of the pagination with a partial offset might start beyond the total number of values
+	// (i.e. for the empty result), in which case it needs to start at the last full page.
+	if last.Offset > 0 && last.Offset >= total {
+		offset := total - pagination.Limit
+		if offset < 0 {
+			offset = 0
+		}
+		last = &query.Pagination{Limit: pagination.Limit, Offset: offset}
+	}
+	links.Last = link(last)
+
+	first, err := pagination.First()
+	if err != nil {
+		return nil, err
+	}
+	links.First = link(first)
+	return links, nil
+}
+
+// paginateModels gets the page of 'models' defined by the 'pagination'.
+func paginateModels(models []mapping.Model, pagination *query.Pagination) []mapping.Model {
+	offset := pagination.Offset
+	if offset > int64(len(models)) {
+		offset = int64(len(models))
+	}
+	end := int64(len(models))
+	if pagination.Limit > 0 && offset+pagination.Limit < end {
+		end = offset + pagination.Limit
+	}
+	return models[offset:end]
+}
+
+// fullPaginationLinks is the rewriter that sets all the pagination link members: 'first', 'last', 'prev' and 'next',
+// in the top-level links. The members not applicable for the list are set to null.
+func fullPaginationLinks(doc document) error {
+	links := map[string]json.RawMessage{}
+	if raw, ok := doc["links"]; ok {
+		if err := json.Unmarshal(raw, &links); err != nil {
+			return err
+		}
+	}
+	for _, name := range []string{"first", "last", "prev", "next"} {
+		if _, ok := links[name]; !ok {
+			links[name] = json.RawMessage("null")
+		}
+	}
+	return doc.set("links", links)
+}
@@ -0,0 +1,98 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// PreferPreviousLinkage is the 'Prefer' header preference that requests the relationship mutation responses
+// to contain the relationship linkage before the change in the 'meta.previous' member.
+const PreferPreviousLinkage = "previous-linkage"
+
+// prefersPreviousLinkage checks if the previous relationship linkage should be returned for given request.
+func (a *API) prefersPreviousLinkage(req *http.Request) bool {
+	if !a.Options.PreviousLinkage {
+		return false
+	}
+	for _, header := range req.Header["Prefer"] {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), PreferPreviousLinkage) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// previousLinkageRewriter creates the rewriter that stores the 'previous' relation models linkage in the document meta.
+// The to-one relationship linkage is a single resource identifier or null.
+func previousLinkageRewriter(relation *mapping.StructField, previous []mapping.Model) (documentRewriter, error) {
+	collection := relation.Relationship().RelatedModelStruct().Collection()
+	linkage := []map[string]interface{}{}
+	for _, model := range previous {
+		if model == nil || model.IsPrimaryKeyZero() {
+			continue
+		}
+		id, err := model.GetPrimaryKeyStringValue()
+		if err != nil {
+			return nil, err
+		}
+		linkage = append(linkage, map[string]interface{}{"type": collection, "id": id})
+	}
+	var value interface{} = linkage
+	if relation.Kind() == mapping.KindRelationshipSingle {
+		value = nil
+		if len(linkage) > 0 {
+			value = linkage[0]
+		}
+	}
+	return func(doc document) error {
+		return doc.mergeMember("meta", map[string]interface{}{"previous": value})
+	}, nil
+}
+
+// currentRelationModels gets the relation models stored in the 'model' for given 'relation'.
+func currentRelationModels(model mapping.Model, relation *mapping.StructField) ([]mapping.Model, error) {
+	if relation.Kind() == mapping.KindRelationshipMultiple {
+		mr, ok := model.(mapping.MultiRelationer)
+		if !ok {
+			return nil, errors.WrapDetf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement MultiRelationer interface", model)
+		}
+		return mr.GetRelationModels(relation)
+	}
+	sr, ok := model.(mapping.SingleRelationer)
+	if !ok {
+		return nil, errors.WrapDetf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement SingleRelationer interface", model)
+	}
+	relationModel, err := sr.GetRelationModel(relation)
+	if err != nil {
+		return nil, err
+	}
+	return []mapping.Model{relationModel}, nil
+}
+
+// sameRelationMembers checks if the 'current' and the 'desired' relation models have the same primary keys.
+func sameRelationMembers(current, desired []mapping.Model) bool {
+	members := func(models []mapping.Model) map[interface{}]struct{} {
+		ids := map[interface{}]struct{}{}
+		for _, model := range models {
+			if model != nil && !model.IsPrimaryKeyZero() {
+				ids[model.GetPrimaryKeyHashableValue()] = struct{}{}
+			}
+		}
+		return ids
+	}
+	currentIDs, desiredIDs := members(current), members(desired)
+	if len(currentIDs) != len(desiredIDs) {
+		return false
+	}
+	for id := range desiredIDs {
+		if _, ok := currentIDs[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+// previousLinkage decodes the 'meta.previous' member of the response document.
+func previousLinkage(t *testing.T, doc document) json.RawMessage {
+	t.Helper()
+	meta := object{}
+	if err := json.Unmarshal(doc["meta"], &meta); err != nil {
+		t.Fatalf("decoding document meta failed: %v", err)
+	}
+	previous, ok := meta["previous"]
+	if !ok {
+		t.Fatal("no previous linkage in the document meta")
+	}
+	return previous
+}
+
+func TestPreviousLinkage(t *testing.T) {
+	headers := []string{"Accept", jsonapi.MimeType, "Prefer", PreferPreviousLinkage}
+
+	t.Run("UpdateToMany", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithPreviousLinkage())
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/authors/2/relationships/blogs", `{"data":[{"type":"blogs","id":"1"}]}`, headers...)
+		expectStatus(t, rec, http.StatusOK)
+
+		var previous []object
+		if err := json.Unmarshal(previousLinkage(t, decodeDocument(t, rec)), &previous); err != nil {
+			t.Fatalf("decoding previous linkage failed: %v", err)
+		}
+		if len(previous) != 2 {
+			t.Fatalf("expected two previous members, got: %d", len(previous))
+		}
+		ids := map[string]bool{previous[0].id(): true, previous[1].id(): true}
+		if !ids["1"] || !ids["2"] {
+			t.Errorf("unexpected previous members: %v", ids)
+		}
+	})
+
+	t.Run("UpdateToOne", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithPreviousLinkage())
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1/relationships/author", `{"data":null}`, headers...)
+		expectStatus(t, rec, http.StatusOK)
+
+		previous := object{}
+		if err := json.Unmarshal(previousLinkage(t, decodeDocument(t, rec)), &previous); err != nil {
+			t.Fatalf("decoding previous linkage failed: %v", err)
+		}
+		if previous.id() != "2" {
+			t.Errorf("expected previous author: 2, got: %s", previous.id())
+		}
+	})
+
+	t.Run("DeleteToMany", func(t *testing.T) {
+		ta := newTestAPI(t, nil, WithPreviousLinkage())
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 2})
+
+		rec := ta.serve(http.MethodDelete, "/authors/2/relationships/blogs", `{"data":[{"type":"blogs","id":"1"}]}`, headers...)
+		expectStatus(t, rec, http.StatusOK)
+
+		var previous []object
+		if err := json.Unmarshal(previousLinkage(t, decodeDocument(t, rec)), &previous); err != nil {
+			t.Fatalf("decoding previous linkage failed: %v", err)
+		}
+		if len(previous) != 2 {
+			t.Errorf("expected two previous members, got: %d", len(previous))
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package jsonapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// ProfileIDsOnly is the json:api profile which makes the list endpoints return only the resource identifiers,
+// without loading the attributes and relationships. The profile needs to be supported with the WithProfiles option.
+const ProfileIDsOnly = "ids-only"
+
+type profilesCtxKey struct{}
+
+// CtxProfiles gets the json:api profiles applied for the request with given context.
+func CtxProfiles(ctx context.Context) []string {
+	profiles, _ := ctx.Value(profilesCtxKey{}).([]string)
+	return profiles
+}
+
+// CtxHasProfile checks if given json:api profile is applied for the request with given context.
+func CtxHasProfile(ctx context.Context, profile string) bool {
+	for _, applied := range CtxProfiles(ctx) {
+		if applied == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// midProfiles negotiates the profiles requested in the 'Accept' and 'Content-Type' headers with the ones
+// supported by the API and stores the applied profiles in the request context.
+func (a *API) midProfiles(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requested := map[string]struct{}{}
+		for _, header := range []string{"Accept", "Content-Type"} {
+			for _, mediaType := range parseMediaTypes(req.Header.Get(header)) {
+				if !mediaType.isJSONAPI() {
+					continue
+				}
+				for _, profile := range mediaType.profiles() {
+					requested[profile] = struct{}{}
+				}
+			}
+		}
+		var applied []string
+		for _, profile := range a.Options.Profiles {
+			if _, ok := requested[profile]; ok {
+				applied = append(applied, profile)
+			}
+		}
+		if len(applied) > 0 {
+			req = req.WithContext(context.WithValue(req.Context(), profilesCtxKey{}, applied))
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
@@ -0,0 +1,44 @@
+package jsonapi
+
+import (
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// queryMeta creates the structured representation of the sorting, filters and pagination applied to the scope 's'.
+func queryMeta(s *query.Scope) map[string]interface{} {
+	sorts := []string{}
+	for _, sort := range s.SortingOrder {
+		name := sort.Field().NeuronName()
+		if sort.Order() == query.DescendingOrder {
+			name = "-" + name
+		}
+		sorts = append(sorts, name)
+	}
+
+	filters := []interface{}{}
+	for _, f := range s.Filters {
+		switch ft := f.(type) {
+		case filter.Simple:
+			filters = append(filters, map[string]interface{}{
+				"field":    ft.StructField.NeuronName(),
+				"operator": ft.Operator.URLAlias,
+				"values":   ft.Values,
+			})
+		default:
+			filters = append(filters, f.String())
+		}
+	}
+
+	meta := map[string]interface{}{
+		"sort":    sorts,
+		"filters": filters,
+	}
+	if s.Pagination != nil {
+		meta["page"] = map[string]interface{}{
+			"limit":  s.Pagination.Limit,
+			"offset": s.Pagination.Offset,
+		}
+	}
+	return meta
+}
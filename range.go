@@ -0,0 +1,45 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/neuronlabs/neuron/query"
+)
+
+// rangeUnit is the range unit used for the list range pagination.
+const rangeUnit = "items"
+
+// parseRangePagination parses the 'Range: items=first-last' header value into the pagination.
+// Returns false if the header is not provided or is not a valid items range.
+func parseRangePagination(header string) (*query.Pagination, bool) {
+	if !strings.HasPrefix(header, rangeUnit+"=") {
+		return nil, false
+	}
+	bounds := strings.Split(strings.TrimPrefix(header, rangeUnit+"="), "-")
+	if len(bounds) != 2 {
+		return nil, false
+	}
+	first, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 64)
+	if err != nil || first < 0 {
+		return nil, false
+	}
+	last, err := strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 64)
+	if err != nil || last < first {
+		return nil, false
+	}
+	return &query.Pagination{Offset: first, Limit: last - first + 1}, true
+}
+
+// writeContentRange sets the 'Content-Range' header for the page of 'count' resources
+// starting at the pagination offset, out of the 'total' number of resources.
+func writeContentRange(rw http.ResponseWriter, pagination *query.Pagination, count int, total int64) {
+	if count == 0 {
+		rw.Header().Set("Content-Range", fmt.Sprintf("%s */%d", rangeUnit, total))
+		return
+	}
+	first := pagination.Offset
+	rw.Header().Set("Content-Range", fmt.Sprintf("%s %d-%d/%d", rangeUnit, first, first+int64(count)-1, total))
+}
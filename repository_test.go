@@ -0,0 +1,256 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+)
+
+// testRepository is the in-memory repository used by the tests. It supports the simple 'equal', 'in', 'is null'
+// and 'not null' filters, where the zero values are null, and records the fieldsets of the update queries.
+type testRepository struct {
+	mu           sync.Mutex
+	models       map[*mapping.ModelStruct][]mapping.Model
+	updateFields []mapping.FieldSet
+}
+
+func newTestRepository() *testRepository {
+	return &testRepository{models: map[*mapping.ModelStruct][]mapping.Model{}}
+}
+
+// store stores the 'models' in the repository.
+func (r *testRepository) store(mStruct *mapping.ModelStruct, models ...mapping.Model) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models[mStruct] = append(r.models[mStruct], models...)
+}
+
+// stored gets the stored model with the primary key 'id'.
+func (r *testRepository) stored(mStruct *mapping.ModelStruct, id interface{}) (mapping.Model, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, model := range r.models[mStruct] {
+		if model.GetPrimaryKeyHashableValue() == id {
+			return model, true
+		}
+	}
+	return nil, false
+}
+
+// ID implements repository.Repository interface.
+func (r *testRepository) ID() string {
+	return "test"
+}
+
+// Count implements repository.Repository interface.
+func (r *testRepository) Count(_ context.Context, s *query.Scope) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	models, err := r.filtered(s)
+	return int64(len(models)), err
+}
+
+// Insert implements repository.Repository interface.
+func (r *testRepository) Insert(_ context.Context, s *query.Scope) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, model := range s.Models {
+		stored, err := copyModel(s.ModelStruct, model)
+		if err != nil {
+			return err
+		}
+		r.models[s.ModelStruct] = append(r.models[s.ModelStruct], stored)
+	}
+	return nil
+}
+
+// Find implements repository.Repository interface.
+func (r *testRepository) Find(_ context.Context, s *query.Scope) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	models, err := r.filtered(s)
+	if err != nil {
+		return err
+	}
+	if s.Pagination != nil && s.Pagination.Limit > 0 && int64(len(models)) > s.Pagination.Limit {
+		models = models[:s.Pagination.Limit]
+	}
+	for _, model := range models {
+		found, err := copyModel(s.ModelStruct, model)
+		if err != nil {
+			return err
+		}
+		s.Models = append(s.Models, found)
+	}
+	return nil
+}
+
+// Update implements repository.Repository interface.
+func (r *testRepository) Update(_ context.Context, s *query.Scope) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(s.Models) == 0 {
+		return 0, errors.Wrap(query.ErrNoModels, "no models to update")
+	}
+	var updated int64
+	if len(s.Filters) > 0 {
+		// The filtered update sets the fields of the first scope model in all the matching models.
+		models, err := r.filtered(s)
+		if err != nil {
+			return 0, err
+		}
+		fieldSet := s.FieldSets[0]
+		r.updateFields = append(r.updateFields, fieldSet)
+		for _, model := range models {
+			if err = setFields(model, s.Models[0], fieldSet); err != nil {
+				return 0, err
+			}
+			updated++
+		}
+		return updated, nil
+	}
+	for i, model := range s.Models {
+		fieldSet := s.FieldSets[0]
+		if len(s.FieldSets) > i {
+			fieldSet = s.FieldSets[i]
+		}
+		r.updateFields = append(r.updateFields, fieldSet)
+		for _, stored := range r.models[s.ModelStruct] {
+			if stored.GetPrimaryKeyHashableValue() != model.GetPrimaryKeyHashableValue() {
+				continue
+			}
+			if err := setFields(stored, model, fieldSet); err != nil {
+				return 0, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// Delete implements repository.Repository interface.
+func (r *testRepository) Delete(_ context.Context, s *query.Scope) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var (
+		kept    []mapping.Model
+		deleted int64
+	)
+	for _, model := range r.models[s.ModelStruct] {
+		matches, err := r.matches(s, model)
+		if err != nil {
+			return 0, err
+		}
+		if matches {
+			deleted++
+			continue
+		}
+		kept = append(kept, model)
+	}
+	r.models[s.ModelStruct] = kept
+	return deleted, nil
+}
+
+// filtered gets the stored models matching the scope filters.
+func (r *testRepository) filtered(s *query.Scope) ([]mapping.Model, error) {
+	var models []mapping.Model
+	for _, model := range r.models[s.ModelStruct] {
+		matches, err := r.matches(s, model)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			models = append(models, model)
+		}
+	}
+	return models, nil
+}
+
+// matches checks if the 'model' matches the scope filters, or the primary keys of the scope models if no filters are set.
+func (r *testRepository) matches(s *query.Scope, model mapping.Model) (bool, error) {
+	if len(s.Filters) == 0 {
+		if len(s.Models) == 0 {
+			return true, nil
+		}
+		for _, scopeModel := range s.Models {
+			if scopeModel.GetPrimaryKeyHashableValue() == model.GetPrimaryKeyHashableValue() {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	fielder, ok := model.(mapping.Fielder)
+	if !ok {
+		return false, errors.Wrapf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement Fielder", model)
+	}
+	for _, f := range s.Filters {
+		simple, ok := f.(filter.Simple)
+		if ok && (simple.Operator == filter.OpIsNull || simple.Operator == filter.OpNotNull) {
+			isZero, err := fielder.IsFieldZero(simple.StructField)
+			if err != nil {
+				return false, err
+			}
+			if isZero != (simple.Operator == filter.OpIsNull) {
+				return false, nil
+			}
+			continue
+		}
+		if !ok || (simple.Operator != filter.OpEqual && simple.Operator != filter.OpIn) {
+			return false, errors.Wrapf(query.ErrInternal, "unsupported test repository filter: %s", f)
+		}
+		value, err := fielder.GetFieldValue(simple.StructField)
+		if err != nil {
+			return false, err
+		}
+		var found bool
+		for _, filterValue := range simple.Values {
+			if fmt.Sprint(filterValue) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// copyModel creates a copy of the 'model' with all its non relation fields.
+func copyModel(mStruct *mapping.ModelStruct, model mapping.Model) (mapping.Model, error) {
+	copied := mapping.NewModel(mStruct)
+	if err := setFields(copied, model, mStruct.Fields()); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// setFields sets the 'fields' values of the 'from' model in the 'to' model.
+func setFields(to, from mapping.Model, fields mapping.FieldSet) error {
+	fromFielder, ok := from.(mapping.Fielder)
+	if !ok {
+		return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement Fielder", from)
+	}
+	toFielder, ok := to.(mapping.Fielder)
+	if !ok {
+		return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement Fielder", to)
+	}
+	for _, field := range fields {
+		if field.Kind() == mapping.KindRelationshipSingle || field.Kind() == mapping.KindRelationshipMultiple {
+			continue
+		}
+		value, err := fromFielder.GetFieldValue(field)
+		if err != nil {
+			return err
+		}
+		if err = toFielder.SetFieldValue(field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package jsonapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+)
+
+// HeaderRequestID is the header that contains the request correlation id.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of the client provided request id.
+const maxRequestIDLength = 128
+
+type requestIDCtxKey struct{}
+
+// CtxRequestID gets the request id stored in given context.
+func CtxRequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDCtxKey{}).(string)
+	return id, ok
+}
+
+// MidRequestID is the middleware that stores the request id in the request context and sets it in the response header.
+// The id is taken from the 'X-Request-ID' request header, if not provided or invalid a new random id is generated.
+// As the id is echoed in the response and written to the logs, only the printable ASCII ids up to 128 bytes are valid.
+func MidRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(HeaderRequestID)
+		if !isValidRequestID(id) {
+			if id != "" {
+				log.Debug2f("Invalid request id provided in the '%s' header - generating a new one", HeaderRequestID)
+			}
+			id = newRequestID()
+		}
+		rw.Header().Set(HeaderRequestID, id)
+		next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), requestIDCtxKey{}, id)))
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		log.Errorf("Generating request id failed: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// isValidRequestID checks if the 'id' is non empty, contains only printable ASCII characters and doesn't exceed
+// the maximum length.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x20 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
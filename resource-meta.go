@@ -0,0 +1,44 @@
+package jsonapi
+
+import (
+	"context"
+
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// ResourceMetaProvider is the interface implemented by the model handlers that computes the meta object
+// for each resource object returned in the get and list responses (i.e. per resource permissions).
+type ResourceMetaProvider interface {
+	ResourceMeta(ctx context.Context, model mapping.Model) (map[string]interface{}, error)
+}
+
+// resourceMetaRewriter computes the meta for all 'models' and creates a rewriter that sets it to their resource objects.
+func resourceMetaRewriter(ctx context.Context, provider ResourceMetaProvider, models []mapping.Model) (documentRewriter, error) {
+	metas := map[string]map[string]interface{}{}
+	for _, model := range models {
+		meta, err := provider.ResourceMeta(ctx, model)
+		if err != nil {
+			return nil, err
+		}
+		if len(meta) == 0 {
+			continue
+		}
+		id, err := model.GetPrimaryKeyStringValue()
+		if err != nil {
+			return nil, err
+		}
+		metas[id] = meta
+	}
+	return func(doc document) error {
+		if len(metas) == 0 {
+			return nil
+		}
+		return doc.forEachResource(func(resource object) error {
+			meta, ok := metas[resource.id()]
+			if !ok {
+				return nil
+			}
+			return resource.mergeMember("meta", meta)
+		})
+	}, nil
+}
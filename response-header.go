@@ -0,0 +1,66 @@
+package jsonapi
+
+import (
+	"context"
+	"net/http"
+)
+
+type responseHeaderCtxKey struct{}
+
+// CtxResponseHeader gets the response header collector stored in the request context. The model handlers could
+// set the response headers (i.e. 'Location' or rate limit headers) in the collector. The headers are written
+// to the response just before its status.
+func CtxResponseHeader(ctx context.Context) (http.Header, bool) {
+	header, ok := ctx.Value(responseHeaderCtxKey{}).(http.Header)
+	return header, ok
+}
+
+// midResponseHeader is the middleware that stores the response header collector in the request context.
+func midResponseHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		header := http.Header{}
+		req = req.WithContext(context.WithValue(req.Context(), responseHeaderCtxKey{}, header))
+		next.ServeHTTP(&headerWriter{ResponseWriter: rw, header: header}, req)
+	})
+}
+
+// headerWriter is the http.ResponseWriter wrapper that writes the collected headers before the response status.
+type headerWriter struct {
+	http.ResponseWriter
+	header      http.Header
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+func (w *headerWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for key, values := range w.header {
+			w.ResponseWriter.Header()[key] = values
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter interface.
+func (w *headerWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush implements http.Flusher interface. It flushes the wrapped writer if it implements http.Flusher.
+func (w *headerWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap gets the wrapped http.ResponseWriter, so that its other optional interfaces could be reached.
+func (w *headerWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
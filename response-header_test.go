@@ -0,0 +1,47 @@
+package jsonapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMidResponseHeader(t *testing.T) {
+	var (
+		flushed   bool
+		unwrapped http.ResponseWriter
+	)
+	rec := httptest.NewRecorder()
+	handler := midResponseHeader(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		header, ok := CtxResponseHeader(req.Context())
+		if !ok {
+			t.Fatal("no response header collector in the request context")
+		}
+		header.Set("X-Rate-Limit-Remaining", "10")
+
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			t.Fatal("response writer doesn't implement http.Flusher")
+		}
+		flusher.Flush()
+		flushed = rec.Flushed
+
+		if u, ok := rw.(interface{ Unwrap() http.ResponseWriter }); ok {
+			unwrapped = u.Unwrap()
+		}
+	}))
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/blogs", nil))
+
+	if !flushed {
+		t.Error("flush is not forwarded to the wrapped response writer")
+	}
+	if unwrapped != rec {
+		t.Error("unwrapped response writer is not the wrapped one")
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status: 200, got: %d", rec.Code)
+	}
+	if got := rec.Header().Get("X-Rate-Limit-Remaining"); got != "10" {
+		t.Errorf("collected header not written before the flush, got: '%s'", got)
+	}
+}
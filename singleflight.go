@@ -0,0 +1,174 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/log"
+)
+
+// flightGroup collapses concurrent identical read requests into a single handler execution.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is the in-flight or finished handler execution shared by the identical requests.
+type flightCall struct {
+	done     chan struct{}
+	response *responseRecorder
+}
+
+// do executes 'handle' only once for all concurrent calls with the same 'key' and returns the recorded response.
+// The calls waiting for the in-flight execution return the 'ctx' error as soon as their own context is done.
+func (g *flightGroup) do(ctx context.Context, key string, handle func(rec *responseRecorder)) (*responseRecorder, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.response, true, nil
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	c := &flightCall{done: make(chan struct{}), response: newResponseRecorder()}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	handle(c.response)
+	return c.response, false, nil
+}
+
+// detachedContext is the context that keeps the values of its parent, but is never canceled. The shared handler
+// execution is detached from the request that started it, so that its cancellation doesn't fail the other requests.
+type detachedContext struct {
+	parent context.Context
+}
+
+// Deadline implements context.Context interface.
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements context.Context interface.
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err implements context.Context interface.
+func (detachedContext) Err() error {
+	return nil
+}
+
+// Value implements context.Context interface.
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// responseRecorder is the http.ResponseWriter that buffers the response, so that it could be written many times.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: http.Header{}, status: http.StatusOK}
+}
+
+// Header implements http.ResponseWriter interface.
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+// Write implements http.ResponseWriter interface.
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// writeTo writes the recorded response into given response writer.
+func (r *responseRecorder) writeTo(rw http.ResponseWriter) {
+	for key, values := range r.header {
+		rw.Header()[key] = append([]string(nil), values...)
+	}
+	rw.WriteHeader(r.status)
+	if _, err := rw.Write(r.body.Bytes()); err != nil {
+		log.Errorf("Writing to response writer failed: %v", err)
+	}
+}
+
+// singleFlightHeaders are the request headers that affect the get and list responses. The requests that differ
+// in any of these headers are never shared. A header read by the read handlers or their middlewares must be added here.
+var singleFlightHeaders = []string{"Accept", "Content-Type", "If-None-Match", "Authorization", "Cookie", "Range"}
+
+// midSingleFlight is the middleware that makes concurrent identical GET requests share a single handler execution.
+// The requests are identified by the singleFlightKey. The shared execution is not canceled with the request that
+// started it, and it runs until completion even if all the waiting requests are canceled.
+func (a *API) midSingleFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		response, shared, err := a.flights.do(req.Context(), a.singleFlightKey(req), func(rec *responseRecorder) {
+			next.ServeHTTP(rec, req.WithContext(detachedContext{parent: req.Context()}))
+		})
+		if err != nil {
+			// The request is canceled while waiting for the shared response - the client is gone.
+			log.Debug2f("[%s] %s waiting for in-flight response canceled: %v", req.Method, req.URL.Path, err)
+			return
+		}
+		if shared {
+			log.Debug3f("[%s] %s shared in-flight response", req.Method, req.URL.Path)
+		}
+		response.writeTo(rw)
+	})
+}
+
+// singleFlightKey creates the key of the request 'req' from its method, URI, debug pretty flag and the singleFlightHeaders.
+// The HeaderScopeEnricher could read any request header, thus if it is set, all the request headers are the part of the key.
+func (a *API) singleFlightKey(req *http.Request) string {
+	sb := &strings.Builder{}
+	sb.WriteString(req.Method)
+	sb.WriteByte('\n')
+	sb.WriteString(req.URL.RequestURI())
+	// The ParamDebugPretty parameter is already removed from the request query.
+	if pretty, _ := req.Context().Value(debugPrettyCtxKey{}).(bool); pretty {
+		sb.WriteString("\n" + ParamDebugPretty)
+	}
+	headers := singleFlightHeaders
+	if a.Options.HeaderScopeEnricher != nil {
+		headers = make([]string, 0, len(req.Header))
+		for name := range req.Header {
+			headers = append(headers, name)
+		}
+		sort.Strings(headers)
+	}
+	for _, name := range headers {
+		sb.WriteByte('\n')
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(req.Header[http.CanonicalHeaderKey(name)], ","))
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package jsonapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testCtxKey struct{}
+
+func TestMidSingleFlightDetached(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var (
+		handlerErr   error
+		handlerValue interface{}
+	)
+	handler := New().midSingleFlight(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+		handlerErr, handlerValue = req.Context().Err(), req.Context().Value(testCtxKey{})
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testCtxKey{}, "value"))
+	req := httptest.NewRequest(http.MethodGet, "/blogs/1", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(rec, req)
+	}()
+	<-started
+	// The cancellation of the request that started the shared execution doesn't cancel it.
+	cancel()
+	close(release)
+	<-done
+
+	if handlerErr != nil {
+		t.Errorf("shared execution context canceled: %v", handlerErr)
+	}
+	if handlerValue != "value" {
+		t.Errorf("expected request context value: 'value', got: %v", handlerValue)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status: %d, got: %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestFlightGroupWaiterCanceled(t *testing.T) {
+	g := &flightGroup{}
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.do(context.Background(), "key", func(rec *responseRecorder) {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	// The waiting call returns as soon as its own context is canceled, without waiting for the shared execution.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response, shared, err := g.do(ctx, "key", func(rec *responseRecorder) {
+		t.Error("handle executed for the in-flight key")
+	})
+	if err != context.Canceled || !shared || response != nil {
+		t.Errorf("expected canceled shared call, got: %v, %v, %v", response, shared, err)
+	}
+	close(release)
+	<-done
+
+	// The finished execution is not shared with the later calls.
+	var executed bool
+	if _, shared, err = g.do(context.Background(), "key", func(rec *responseRecorder) { executed = true }); err != nil || shared || !executed {
+		t.Errorf("expected new execution, got: shared: %v, executed: %v, err: %v", shared, executed, err)
+	}
+}
@@ -0,0 +1,103 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/neuronlabs/neuron-extensions/server/http/httputil"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// Tracer is the interface used to trace the API requests (i.e. an adapter of the OpenTelemetry tracer).
+// The span should be started as a child of the trace propagated in the 'req' headers, with given 'name'
+// and 'attributes'. The returned context should contain the started span, so that the model handlers
+// could create its child spans.
+type Tracer interface {
+	StartSpan(req *http.Request, name string, attributes map[string]interface{}) (context.Context, Span)
+}
+
+// Span is the traced request span started by the Tracer.
+type Span interface {
+	// RecordError records the error returned by the API request.
+	RecordError(err error)
+	// End ends the span with the response HTTP 'status'.
+	End(status int)
+}
+
+type spanCtxKey struct{}
+
+// ctxSpan gets the request span stored in the context.
+func ctxSpan(ctx context.Context) (Span, bool) {
+	span, ok := ctx.Value(spanCtxKey{}).(Span)
+	return span, ok
+}
+
+// midTracing is the middleware that traces the request of the stored endpoint with the Tracer span.
+// The span is named by the endpoint HTTP method, collection and query method, i.e. 'GET articles list'.
+func (a *API) midTracing(next http.Handler) http.Handler {
+	if a.Options.Tracer == nil {
+		return next
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		endpoint, ok := httputil.CtxGetEndpoint(req.Context())
+		if !ok {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		attributes := map[string]interface{}{
+			"http.method": endpoint.HTTPMethod,
+			"http.route":  endpoint.Path,
+			"model":       endpoint.ModelStruct.Collection(),
+		}
+		if endpoint.Relation != nil {
+			attributes["relation"] = endpoint.Relation.NeuronName()
+		}
+		name := fmt.Sprintf("%s %s %s", endpoint.HTTPMethod, endpoint.ModelStruct.Collection(), queryMethodName(endpoint.QueryMethod))
+		ctx, span := a.Options.Tracer.StartSpan(req, name, attributes)
+		sw := &statusWriter{ResponseWriter: rw, status: http.StatusOK}
+		defer func() {
+			span.End(sw.status)
+		}()
+		next.ServeHTTP(sw, req.WithContext(context.WithValue(ctx, spanCtxKey{}, span)))
+	})
+}
+
+// statusWriter is the http.ResponseWriter wrapper that records the response status.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// queryMethodName gets the name of the query 'method' used in the span names.
+func queryMethodName(method query.Method) string {
+	switch method {
+	case query.Insert:
+		return "insert"
+	case query.InsertRelationship:
+		return "insert-relationship"
+	case query.Get:
+		return "get"
+	case query.GetRelationship:
+		return "get-relationship"
+	case query.GetRelated:
+		return "get-related"
+	case query.List:
+		return "list"
+	case query.Update:
+		return "update"
+	case query.UpdateRelationship:
+		return "update-relationship"
+	case query.Delete:
+		return "delete"
+	case query.DeleteRelationship:
+		return "delete-relationship"
+	}
+	return "unknown"
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 	"github.com/neuronlabs/neuron/server"
 )
 
@@ -35,15 +37,14 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 			log.Debugf("[UPDATE-RELATIONSHIP][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		model := mapping.NewModel(mStruct)
 		if err := model.SetPrimaryKeyStringValue(id); err != nil {
-			err := httputil.ErrInvalidQueryParameter()
-			err.Detail = "provided invalid 'id' value"
-			a.marshalErrors(rw, 0, err)
+			log.Debugf("[UPDATE-RELATIONSHIP][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
+			a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
 			return
 		}
 
@@ -51,7 +52,13 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 		if model.IsPrimaryKeyZero() {
 			err := httputil.ErrInvalidQueryParameter()
 			err.Detail = "provided zero value primary key"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+
+		if err := a.checkRelationshipBody(req); err != nil {
+			log.Debugf("[UPDATE-RELATIONSHIP][%s][%s] empty request body", mStruct, relation)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
@@ -62,7 +69,13 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 			ModelStruct:     relation.Relationship().RelatedModelStruct(),
 		})
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errUnmarshal(err))
+			return
+		}
+
+		if err := a.checkRelationshipMembers(payload); err != nil {
+			log.Debugf("[UPDATE-RELATIONSHIP][%s][%s] %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 400, err)
 			return
 		}
 
@@ -71,18 +84,19 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 			if relation.IsPrimaryKeyZero() {
 				err := httputil.ErrInvalidJSONFieldValue()
 				err.Detail = "one of provided relationships doesn't have it's primary key value stored"
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 		}
 
-		// Create a query scope.
-		s := query.NewScope(mStruct, model)
+		// Create a query scope that gets only the primary key with the current relation members.
+		s := query.NewScope(mStruct)
 		s.FieldSets = []mapping.FieldSet{{mStruct.Primary()}}
+		s.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
 
 		// Include relation values.
 		if err = s.Include(relation, relation.Relationship().RelatedModelStruct().Primary()); err != nil {
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 			return
 		}
 
@@ -91,7 +105,7 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextUpdateRelationer); ok {
 				if ctx, err = w.UpdateRelationsWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -99,7 +113,7 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 		// Doing changes in the relationship requires to run it in a transaction.
 		tx, err := database.Begin(ctx, a.DB, nil)
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		defer func() {
@@ -110,46 +124,73 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 			}
 		}()
 
-		_, err = a.getHandleChain(ctx, tx, s)
+		var current *codec.Payload
+		current, err = a.getHandleChain(ctx, tx, s)
+		if err == nil && len(current.Data) == 0 {
+			err = errors.WrapDetf(query.ErrNoResult, "resource not found")
+		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if errors.Is(err, query.ErrNoResult) {
+				log.Debug2f("[UPDATE-RELATIONSHIP][%s][%s] resource: '%s' not found", mStruct, relation, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
+			return
+		}
+		// The current relation members are taken from the stored model.
+		model = current.Data[0]
+
+		var previous []mapping.Model
+		if previous, err = currentRelationModels(model, relation); err != nil {
+			log.Errorf("[UPDATE-RELATIONSHIP][%s][%s] getting current relations failed: %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+			return
+		}
+		if err = a.checkLinkageIfMatch(req, previous); err != nil {
+			log.Debugf("[UPDATE-RELATIONSHIP][%s][%s] If-Match precondition failed: %v", mStruct, relation, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		if hasModelHandler {
 			if beforeHandler, ok := modelHandler.(server.BeforeUpdateRelationsHandler); ok {
 				if err = beforeHandler.HandleBeforeUpdateRelations(ctx, tx, model, payload); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
 		}
 
-		// Handle set relationships.
-		handler, ok := modelHandler.(server.SetRelationsHandler)
-		if !ok {
-			handler = a.defaultHandler
-		}
-		var result *codec.Payload
-		result, err = handler.HandleSetRelations(ctx, tx, model, payload.Data, relation)
-		if err != nil {
-			a.marshalErrors(rw, 0, err)
-			return
-		}
+		// Replacing the relationship with its current members is a no-op, so that the request is idempotent.
+		changed := !sameRelationMembers(previous, payload.Data)
+		result := &codec.Payload{}
+		if changed {
+			// Handle set relationships.
+			handler, ok := modelHandler.(server.SetRelationsHandler)
+			if !ok {
+				handler = a.defaultHandler
+			}
+			result, err = handler.HandleSetRelations(ctx, tx, model, payload.Data, relation)
+			if err != nil {
+				a.marshalErrors(rw, req, 0, err)
+				return
+			}
 
-		// Do the after delete handler.
-		if hasModelHandler {
-			if afterHandler, ok := modelHandler.(server.AfterUpdateRelationsHandler); ok {
-				if err = afterHandler.HandleAfterUpdateRelations(ctx, tx, model, payload.Data, result); err != nil {
-					a.marshalErrors(rw, 0, err)
-					return
+			// Do the after delete handler.
+			if hasModelHandler {
+				if afterHandler, ok := modelHandler.(server.AfterUpdateRelationsHandler); ok {
+					if err = afterHandler.HandleAfterUpdateRelations(ctx, tx, model, payload.Data, result); err != nil {
+						a.marshalErrors(rw, req, 0, err)
+						return
+					}
 				}
 			}
 		}
 
 		if err = tx.Commit(); err != nil {
 			log.Errorf("Cannot commit a transaction: %v", err)
-			a.marshalErrors(rw, 500, httputil.ErrInternalError())
+			a.marshalErrors(rw, req, 0, errCommit(err))
 			return
 		}
 
@@ -175,12 +216,27 @@ func (a *API) handleUpdateRelationship(mStruct *mapping.ModelStruct, relation *m
 		result.FieldSets = []mapping.FieldSet{{relation.Relationship().RelatedModelStruct().Primary()}}
 		result.MarshalLinks = codec.LinkOptions{
 			Type:          link,
-			BaseURL:       a.Options.PathPrefix,
+			BaseURL:       a.linksBaseURL(),
 			RootID:        id,
 			Collection:    mStruct.Collection(),
 			RelationField: relation.NeuronName(),
 		}
 		result.MarshalSingularFormat = relation.Kind() == mapping.KindRelationshipSingle
-		a.marshalPayload(rw, result, http.StatusOK)
+		var rewriters []documentRewriter
+		if a.prefersPreviousLinkage(req) {
+			rewriter, err := previousLinkageRewriter(relation, previous)
+			if err != nil {
+				log.Errorf("[UPDATE-RELATIONSHIP][%s][%s] getting previous relationship linkage failed: %v", mStruct, relation, err)
+				a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
+				return
+			}
+			rewriters = append(rewriters, rewriter)
+		}
+		if a.Options.RelationshipChangedMeta {
+			rewriters = append(rewriters, func(doc document) error {
+				return doc.mergeMember("meta", map[string]interface{}{"changed": changed})
+			})
+		}
+		a.marshalPayload(rw, req, result, http.StatusOK, rewriters...)
 	}
 }
@@ -0,0 +1,72 @@
+package jsonapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+)
+
+func TestHandleUpdateRelationshipClear(t *testing.T) {
+	t.Run("ToMany", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/authors/2/relationships/blogs", `{"data":[]}`)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusNoContent {
+			t.Fatalf("unexpected status: %d, body: %s", rec.Code, rec.Body.String())
+		}
+
+		for _, id := range []int{1, 2} {
+			if blog := ta.storedBlog(t, id); blog.AuthorID != 0 {
+				t.Errorf("expected blog: %d without author, got author id: %d", id, blog.AuthorID)
+			}
+		}
+	})
+
+	t.Run("ToOne", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1/relationships/author", `{"data":null}`)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusNoContent {
+			t.Fatalf("unexpected status: %d, body: %s", rec.Code, rec.Body.String())
+		}
+
+		if blog := ta.storedBlog(t, 1); blog.AuthorID != 0 {
+			t.Errorf("expected blog without author, got author id: %d", blog.AuthorID)
+		}
+	})
+
+	t.Run("MissingParent", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1/relationships/author", `{"data":null}`)
+		expectStatus(t, rec, http.StatusNotFound)
+	})
+}
+
+func TestHandleUpdateRelationshipIfMatch(t *testing.T) {
+	ta := newTestAPI(t, nil, WithETag())
+	ta.storeBlogs(&Blog{ID: 1, AuthorID: 2}, &Blog{ID: 2, AuthorID: 2})
+
+	rec := ta.serve(http.MethodGet, "/authors/2/relationships/blogs", "", "Accept", jsonapi.MimeType)
+	expectStatus(t, rec, http.StatusOK)
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag in the get relationship response")
+	}
+
+	rec = ta.serve(http.MethodPatch, "/authors/2/relationships/blogs", `{"data":[{"type":"blogs","id":"1"}]}`,
+		"Accept", jsonapi.MimeType, "If-Match", etag)
+	expectStatus(t, rec, http.StatusOK)
+
+	if blog := ta.storedBlog(t, 2); blog.AuthorID != 0 {
+		t.Errorf("expected blog: 2 without author, got author id: %d", blog.AuthorID)
+	}
+
+	// The linkage has changed, so the same ETag no longer matches.
+	rec = ta.serve(http.MethodPatch, "/authors/2/relationships/blogs", `{"data":[]}`,
+		"Accept", jsonapi.MimeType, "If-Match", etag)
+	expectStatus(t, rec, http.StatusPreconditionFailed)
+}
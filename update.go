@@ -9,6 +9,7 @@ import (
 	"github.com/neuronlabs/neuron-extensions/server/http/log"
 	"github.com/neuronlabs/neuron/codec"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/query/filter"
@@ -30,7 +31,7 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 			log.Debugf("[PATCH][%s] Empty id params", mStruct.Collection())
 			err := httputil.ErrBadRequest()
 			err.Detail = "Provided empty 'id' in url"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 		// unmarshal the input from the request body.
@@ -38,7 +39,7 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		payload, err := pu.UnmarshalPayload(req.Body, codec.UnmarshalOptions{StrictUnmarshal: a.Options.StrictUnmarshal, ModelStruct: mStruct})
 		if err != nil {
 			log.Debugf("Unmarshal scope for: '%s' failed: %v", mStruct.Collection(), err)
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, errUnmarshal(err))
 			return
 		}
 
@@ -46,37 +47,44 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		case 0:
 			err := httputil.ErrInvalidInput()
 			err.Detail = "no models found in the input"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		case 1:
 		default:
 			err := httputil.ErrInvalidInput()
 			err.Detail = "bulk update is not implemented yet"
-			a.marshalErrors(rw, 0, err)
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
 		model := payload.Data[0]
 		if model.IsPrimaryKeyZero() {
-			err = model.SetPrimaryKeyStringValue(id)
+			if err = model.SetPrimaryKeyStringValue(id); err != nil {
+				log.Debugf("[PATCH][%s] Invalid URL id value: '%s': '%v'", mStruct.Collection(), id, err)
+				a.marshalErrors(rw, req, 0, errInvalidID(mStruct))
+				return
+			}
 		} else {
 			unmarshaledID, err := model.GetPrimaryKeyStringValue()
 			if err != nil {
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 			if unmarshaledID != id {
 				err := httputil.ErrInvalidInput()
 				err.Detail = "provided input model 'id' differs from the one in the URI"
 				log.Debug2f("[PATCH][%s] %s", mStruct.Collection(), err.Detail)
-				a.marshalErrors(rw, 0, err)
+				a.marshalErrors(rw, req, 0, err)
 				return
 			}
 		}
 
+		// The unmarshaled fieldset contains only the fields present in the input document - including the ones
+		// explicitly set to null. The omitted fields are not updated.
 		unmarshaledFieldset := payload.FieldSets[0]
 		relations := mapping.FieldSet{}
 		fields := mapping.FieldSet{}
+		var belongsTo []*mapping.StructField
 		for _, field := range unmarshaledFieldset {
 			switch field.Kind() {
 			case mapping.KindRelationshipMultiple, mapping.KindRelationshipSingle:
@@ -85,25 +93,32 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 					relationer, ok := model.(mapping.SingleRelationer)
 					if !ok {
 						log.Errorf("Model: '%s' doesn't implement mapping.SingleRelationer interface", mStruct.Collection())
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 						return
 					}
 					relation, err := relationer.GetRelationModel(field)
 					if err != nil {
-						a.marshalErrors(rw, 0, err)
+						a.marshalErrors(rw, req, 0, err)
 						return
 					}
 					fielder, ok := model.(mapping.Fielder)
 					if !ok {
 						log.Errorf("Model: '%s' doesn't implement mapping.SingleRelationer interface", mStruct.Collection())
-						a.marshalErrors(rw, 500, httputil.ErrInternalError())
+						a.marshalErrors(rw, req, 500, httputil.ErrInternalError())
 						return
 					}
-					if err = fielder.SetFieldValue(field.Relationship().ForeignKey(), relation.GetPrimaryKeyValue()); err != nil {
-						a.marshalErrors(rw, 0, err)
+					if relation == nil {
+						// Explicit null relationship data clears the foreign key.
+						err = fielder.SetFieldZeroValue(field.Relationship().ForeignKey())
+					} else {
+						err = fielder.SetFieldValue(field.Relationship().ForeignKey(), relation.GetPrimaryKeyValue())
+					}
+					if err != nil {
+						a.marshalErrors(rw, req, 0, err)
 						return
 					}
 					fields = append(fields, field.Relationship().ForeignKey())
+					belongsTo = append(belongsTo, field)
 					continue
 				}
 				// All the other foreign relations should be post insert.
@@ -127,7 +142,13 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if hasModelHandler {
 			if w, ok := modelHandler.(server.WithContextUpdater); ok {
 				if ctx, err = w.UpdateWithContext(ctx); err != nil {
-					a.marshalErrors(rw, 0, err)
+					a.marshalErrors(rw, req, 0, err)
+					return
+				}
+			}
+			if normalizer, ok := modelHandler.(AttributeNormalizer); ok {
+				if err = normalizer.NormalizeAttributes(ctx, model, payload.FieldSets[0]); err != nil {
+					a.marshalErrors(rw, req, 0, err)
 					return
 				}
 			}
@@ -137,7 +158,9 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 				txOpts = t.UpdateWithTransaction()
 			}
 		}
-		if len(relations) > 0 && !isTransactioner {
+		// The referenced belongs to resources are checked within the same transaction as the update.
+		validateBelongsTo := a.Options.ValidateBelongsToExists && len(belongsTo) > 0
+		if (len(relations) > 0 || validateBelongsTo) && !isTransactioner {
 			isTransactioner = true
 		}
 
@@ -153,14 +176,28 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		var result *codec.Payload
 		if isTransactioner {
 			err = database.RunInTransaction(ctx, db, txOpts, func(db database.DB) error {
+				if validateBelongsTo {
+					if err := a.checkBelongsToExist(ctx, db, model, belongsTo); err != nil {
+						return err
+					}
+				}
 				result, err = a.fullUpdateHandlerChain(ctx, db, payload, model, hasJsonapiMimeType)
 				return err
 			})
+			if errors.Is(err, query.ErrViolation) {
+				// Deferred constraints are checked on the transaction commit.
+				err = errCommit(err)
+			}
 		} else {
 			result, err = a.fullUpdateHandlerChain(ctx, db, payload, model, hasJsonapiMimeType)
 		}
 		if err != nil {
-			a.marshalErrors(rw, 0, err)
+			if errors.Is(err, query.ErrNoResult) {
+				log.Debug2f("[PATCH][%s] resource: '%s' not found", mStruct, id)
+				a.marshalErrors(rw, req, http.StatusNotFound, errResourceNotFound(mStruct, id))
+				return
+			}
+			a.marshalErrors(rw, req, 0, err)
 			return
 		}
 
@@ -181,13 +218,13 @@ func (a *API) handleUpdate(mStruct *mapping.ModelStruct) http.HandlerFunc {
 		if result.MarshalLinks.Type == codec.NoLink {
 			result.MarshalLinks = codec.LinkOptions{
 				Type:       linkType,
-				BaseURL:    a.Options.PathPrefix,
+				BaseURL:    a.linksBaseURL(),
 				RootID:     httputil.CtxMustGetID(ctx),
 				Collection: mStruct.Collection(),
 			}
 		}
 		result.MarshalSingularFormat = true
-		a.marshalPayload(rw, result, http.StatusOK)
+		a.marshalPayload(rw, req, result, http.StatusOK)
 	}
 }
 
@@ -200,8 +237,13 @@ func (a *API) fullUpdateHandlerChain(ctx context.Context, db database.DB, payloa
 		return result, nil
 	}
 
-	// Prepare the scope for the api.GetHandler.
 	mStruct := payload.ModelStruct
+	if completer, ok := a.handlers[mStruct].(UpdateResultCompleter); ok && len(result.Data) == 1 && completer.IsUpdateResultComplete(result) {
+		// The handler result already contains the complete model - no need to refetch it.
+		return result, nil
+	}
+
+	// Prepare the scope for the api.GetHandler.
 	getScope := query.NewScope(mStruct)
 	getScope.FieldSets = []mapping.FieldSet{mStruct.Fields()}
 	getScope.Filter(filter.New(mStruct.Primary(), filter.OpEqual, model.GetPrimaryKeyValue()))
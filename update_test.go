@@ -0,0 +1,151 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/neuronlabs/neuron-extensions/codec/jsonapi"
+
+	"github.com/neuronlabs/neuron/database"
+)
+
+// nonQueryUpdaterDB is the database.DB wrapper which doesn't implement the database.QueryUpdater interface.
+type nonQueryUpdaterDB struct {
+	database.DB
+}
+
+func TestHandleUpdatePartial(t *testing.T) {
+	t.Run("Attribute", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body", AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"title":"x"}}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		blog := ta.storedBlog(t, 1)
+		if blog.Title != "x" {
+			t.Errorf("expected title: 'x', got: '%s'", blog.Title)
+		}
+		if blog.Body != "body" || blog.AuthorID != 2 {
+			t.Errorf("omitted fields changed: body: '%s', author id: %d", blog.Body, blog.AuthorID)
+		}
+		if len(ta.repo.updateFields) != 1 {
+			t.Fatalf("expected single update query, got: %d", len(ta.repo.updateFields))
+		}
+		for _, field := range ta.repo.updateFields[0] {
+			if field.NeuronName() != "title" {
+				t.Errorf("field: '%s' not present in the payload is updated", field.NeuronName())
+			}
+		}
+	})
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body", AuthorID: 2}, &Blog{ID: 2, AuthorID: 3})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","relationships":{"author":{"data":{"type":"authors","id":"3"}}}}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		blog := ta.storedBlog(t, 1)
+		if blog.AuthorID != 3 {
+			t.Errorf("expected author id: 3, got: %d", blog.AuthorID)
+		}
+		if blog.Title != "title" || blog.Body != "body" {
+			t.Errorf("omitted fields changed: title: '%s', body: '%s'", blog.Title, blog.Body)
+		}
+	})
+
+	t.Run("Refetch", func(t *testing.T) {
+		ta := newTestAPI(t, nil)
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body", AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"title":"x"}}}`, "Accept", jsonapi.MimeType)
+		expectStatus(t, rec, http.StatusOK)
+
+		resource := primaryResource(t, decodeDocument(t, rec))
+		attributes := map[string]interface{}{}
+		if err := json.Unmarshal(resource["attributes"], &attributes); err != nil {
+			t.Fatalf("decoding attributes failed: %v", err)
+		}
+		if attributes["title"] != "x" {
+			t.Errorf("expected title: 'x', got: '%v'", attributes["title"])
+		}
+		// The refetched resource contains the stored values of the omitted fields.
+		if attributes["body"] != "body" {
+			t.Errorf("expected body: 'body', got: '%v'", attributes["body"])
+		}
+	})
+
+	t.Run("NonQueryUpdater", func(t *testing.T) {
+		ta := newTestAPI(t, func(db database.DB) database.DB { return nonQueryUpdaterDB{DB: db} })
+		ta.storeBlogs(&Blog{ID: 1, Title: "title", Body: "body", AuthorID: 2})
+
+		rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"title":"x"}}}`)
+		expectStatus(t, rec, http.StatusNoContent)
+
+		blog := ta.storedBlog(t, 1)
+		if blog.Title != "x" || blog.Body != "body" || blog.AuthorID != 2 {
+			t.Errorf("unexpected blog after update: %+v", blog)
+		}
+	})
+}
+
+func TestHandleUpdateNotFound(t *testing.T) {
+	ta := newTestAPI(t, nil)
+	ta.storeBlogs(&Blog{ID: 1, Title: "title"})
+
+	rec := ta.serve(http.MethodPatch, "/blogs/2", `{"data":{"type":"blogs","id":"2","attributes":{"title":"x"}}}`, "Accept", jsonapi.MimeType)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	if _, ok := ta.repo.stored(ta.blogs, 2); ok {
+		t.Error("the missing resource is created by the update")
+	}
+	if blog := ta.storedBlog(t, 1); blog.Title != "title" {
+		t.Errorf("other resource changed: %+v", blog)
+	}
+}
+
+func TestHandleUpdateExplicitNull(t *testing.T) {
+	for name, wrapDB := range map[string]func(db database.DB) database.DB{
+		"QueryUpdater":    nil,
+		"NonQueryUpdater": func(db database.DB) database.DB { return nonQueryUpdaterDB{DB: db} },
+	} {
+		wrapDB := wrapDB
+		t.Run(name, func(t *testing.T) {
+			subtitle := "subtitle"
+
+			t.Run("Null", func(t *testing.T) {
+				ta := newTestAPI(t, wrapDB)
+				ta.storeBlogs(&Blog{ID: 1, Title: "title", Subtitle: &subtitle, Body: "body"})
+
+				rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"subtitle":null}}}`)
+				expectStatus(t, rec, http.StatusNoContent)
+
+				blog := ta.storedBlog(t, 1)
+				if blog.Subtitle != nil {
+					t.Errorf("expected cleared subtitle, got: '%s'", *blog.Subtitle)
+				}
+				if blog.Title != "title" || blog.Body != "body" {
+					t.Errorf("omitted fields changed: title: '%s', body: '%s'", blog.Title, blog.Body)
+				}
+			})
+
+			t.Run("Omitted", func(t *testing.T) {
+				ta := newTestAPI(t, wrapDB)
+				ta.storeBlogs(&Blog{ID: 1, Title: "title", Subtitle: &subtitle, Body: "body"})
+
+				rec := ta.serve(http.MethodPatch, "/blogs/1", `{"data":{"type":"blogs","id":"1","attributes":{"body":"x"}}}`)
+				expectStatus(t, rec, http.StatusNoContent)
+
+				blog := ta.storedBlog(t, 1)
+				if blog.Subtitle == nil || *blog.Subtitle != subtitle {
+					t.Errorf("omitted subtitle changed: %v", blog.Subtitle)
+				}
+				if blog.Body != "x" {
+					t.Errorf("expected body: 'x', got: '%s'", blog.Body)
+				}
+			})
+		})
+	}
+}